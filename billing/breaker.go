@@ -0,0 +1,198 @@
+package billing
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultFailureThreshold = 5
+	defaultOpenTimeout      = 30 * time.Second
+	defaultHalfOpenRequests = 1
+)
+
+// ErrCircuitOpen is returned instead of sending a request while the circuit
+// breaker is open.
+var ErrCircuitOpen = errors.New("Circuit breaker is open: Fluxrate API is unavailable")
+
+// CircuitState is the state of the circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets all requests through.
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen rejects all requests until OpenTimeout has passed.
+	CircuitOpen
+
+	// CircuitHalfOpen lets a limited number of trial requests through to
+	// find out whether the API has recovered.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreakerConfig configures the circuit breaker shared by all requests
+// of an SDK instance. Only network errors, timeouts and 5xx responses count
+// as failures; other error responses show that the API is up.
+type CircuitBreakerConfig struct {
+	// Disabled turns the circuit breaker off
+	Disabled bool
+
+	// FailureThreshold is the number of consecutive failures that opens
+	// the circuit (default: 5)
+	FailureThreshold int
+
+	// OpenTimeout is how long the circuit stays open before trial requests
+	// are let through (default: 30 seconds)
+	OpenTimeout time.Duration
+
+	// HalfOpenRequests is the number of concurrent trial requests allowed
+	// while half-open (default: 1)
+	HalfOpenRequests int
+
+	// OnStateChange is called after every state transition (optional)
+	OnStateChange func(from, to CircuitState)
+}
+
+type circuitBreaker struct {
+	config CircuitBreakerConfig
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	trials   int
+}
+
+func newCircuitBreaker(config CircuitBreakerConfig) *circuitBreaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = defaultFailureThreshold
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = defaultOpenTimeout
+	}
+	if config.HalfOpenRequests <= 0 {
+		config.HalfOpenRequests = defaultHalfOpenRequests
+	}
+	return &circuitBreaker{config: config}
+}
+
+// allow reports whether a request may be sent now. Every allowed request
+// must be followed by a call to record or abandon.
+func (b *circuitBreaker) allow() bool {
+	if b.config.Disabled {
+		return true
+	}
+
+	b.mu.Lock()
+	from := b.state
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.config.OpenTimeout {
+		b.state = CircuitHalfOpen
+		b.trials = 0
+	}
+
+	allowed := true
+	switch b.state {
+	case CircuitOpen:
+		allowed = false
+	case CircuitHalfOpen:
+		allowed = b.trials < b.config.HalfOpenRequests
+		if allowed {
+			b.trials++
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+	return allowed
+}
+
+// record updates the breaker with the outcome of an allowed request.
+func (b *circuitBreaker) record(err error) {
+	if b.config.Disabled {
+		return
+	}
+
+	b.mu.Lock()
+	from := b.state
+	if isOutage(err) {
+		b.failures++
+		if b.state == CircuitHalfOpen || b.failures >= b.config.FailureThreshold {
+			b.state = CircuitOpen
+			b.openedAt = time.Now()
+		}
+	} else {
+		b.failures = 0
+		b.state = CircuitClosed
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+}
+
+// abandon releases an allowed request whose outcome says nothing about the
+// API, such as one cancelled by the caller.
+func (b *circuitBreaker) abandon() {
+	if b.config.Disabled {
+		return
+	}
+
+	b.mu.Lock()
+	if b.state == CircuitHalfOpen && b.trials > 0 {
+		b.trials--
+	}
+	b.mu.Unlock()
+}
+
+func (b *circuitBreaker) current() CircuitState {
+	if b.config.Disabled {
+		return CircuitClosed
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.config.OpenTimeout {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+func (b *circuitBreaker) notify(from, to CircuitState) {
+	if from != to && b.config.OnStateChange != nil {
+		b.config.OnStateChange(from, to)
+	}
+}
+
+// isOutage reports whether err indicates that the API is unavailable, as
+// opposed to a request the API rejected.
+func isOutage(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500 || apiErr.StatusCode == http.StatusRequestTimeout
+	}
+	return true
+}
+
+// CircuitState returns the current state of the circuit breaker.
+func (s *SDK) CircuitState() CircuitState {
+	return s.breaker.current()
+}
@@ -0,0 +1,59 @@
+package billing
+
+import (
+	"bytes"
+	"compress/gzip"
+)
+
+const defaultCompressionThreshold = 1024
+
+// Codec compresses request bodies. Implementations must be safe for
+// concurrent use. Codecs for encodings outside the standard library, such
+// as zstd, can be plugged in by implementing this interface.
+type Codec interface {
+	// Encoding returns the Content-Encoding token, e.g. "gzip" or "zstd"
+	Encoding() string
+
+	// Encode returns the compressed form of src
+	Encode(src []byte) ([]byte, error)
+}
+
+// GzipCodec compresses request bodies with gzip.
+type GzipCodec struct {
+	// Level is the gzip compression level (default: gzip.DefaultCompression)
+	Level int
+}
+
+// Encoding returns "gzip".
+func (c GzipCodec) Encoding() string {
+	return "gzip"
+}
+
+// Encode compresses src with gzip.
+func (c GzipCodec) Encode(src []byte) ([]byte, error) {
+	level := c.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(src); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// shouldCompress reports whether a request body of this size is compressed.
+// Compression is switched off for good once the server rejects it.
+func (s *SDK) shouldCompress(payload []byte) bool {
+	return s.config.Compression != nil &&
+		len(payload) >= s.config.CompressionThreshold &&
+		!s.compressionDisabled.Load()
+}
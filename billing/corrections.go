@@ -0,0 +1,212 @@
+package billing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Metadata keys that tag corrections, so they can be told apart from
+// regular usage in usage queries and invoices.
+const (
+	CorrectionKey       = "correction"
+	CorrectionReasonKey = "correction_reason"
+)
+
+// ErrEventNotFound is returned when the API does not know the event.
+var ErrEventNotFound = errors.New("Event not found")
+
+// AdjustmentParams describes a correction of previously tracked usage.
+type AdjustmentParams struct {
+	// MeterToken is the meter to correct
+	MeterToken string
+
+	// CustomerExternalID is the customer whose usage is corrected
+	CustomerExternalID string
+
+	// Quantity is the signed correction; negative values reduce usage
+	Quantity float64
+
+	// QuantityDecimal is an exact alternative to Quantity (optional)
+	QuantityDecimal *Decimal
+
+	// Timestamp places the adjustment in a billing period (default: now)
+	Timestamp *time.Time
+
+	// Reason explains the correction for the audit trail (required)
+	Reason string
+
+	// EventID is the event being corrected, if there is one (optional)
+	EventID string
+
+	// IdempotencyKey prevents the adjustment from being applied twice
+	// (default: generated)
+	IdempotencyKey string
+
+	// Metadata is optional additional data
+	Metadata map[string]interface{}
+}
+
+// VoidParams identifies an event to void, either by EventID or by
+// MeterToken and IdempotencyKey.
+type VoidParams struct {
+	// EventID is the ID returned when the event was tracked
+	EventID string
+
+	// MeterToken and IdempotencyKey identify the event if EventID is empty
+	MeterToken     string
+	IdempotencyKey string
+
+	// Reason explains the void for the audit trail (required)
+	Reason string
+}
+
+// Adjust records a signed correction of a customer's usage, e.g. to undo
+// an hour of double-counted requests. It is sent right away, not batched,
+// and tagged with CorrectionKey "adjustment" and the reason.
+func (s *SDK) Adjust(ctx context.Context, params AdjustmentParams) (*TrackEventResponse, error) {
+	if err := validateAdjustment(params); err != nil {
+		return nil, err
+	}
+	if !s.begin() {
+		return nil, ErrClosed
+	}
+	defer s.ops.Done()
+
+	// A generated key still protects the retries below
+	key := params.IdempotencyKey
+	if key == "" {
+		key = newEventID()
+	}
+	ts := time.Now().UTC()
+	if params.Timestamp != nil {
+		ts = params.Timestamp.UTC()
+	}
+
+	body := map[string]interface{}{
+		"meter_token":          params.MeterToken,
+		"customer_external_id": params.CustomerExternalID,
+		"quantity":             params.Quantity,
+		"timestamp":            ts.Format(time.RFC3339Nano),
+		"reason":               params.Reason,
+		"idempotency_key":      key,
+		"metadata":             correctionMetadata(params.Metadata, "adjustment", params.Reason),
+	}
+	if params.QuantityDecimal != nil {
+		body["quantity"] = json.Number(params.QuantityDecimal.String())
+	}
+	if params.EventID != "" {
+		body["event_id"] = params.EventID
+	}
+
+	var result TrackEventResponse
+	err := s.doWithRetry(ctx, apiRequest{
+		method: "POST",
+		path:   "/sdk/adjustments",
+		body:   body,
+		action: "adjust usage",
+	}, &result)
+	if err != nil {
+		return nil, notFound(err, ErrEventNotFound)
+	}
+	return &result, nil
+}
+
+// VoidEvent voids a tracked event so it no longer counts towards usage.
+// The event stays visible, tagged with CorrectionKey "void" and the reason.
+// An unknown event is reported as ErrEventNotFound.
+func (s *SDK) VoidEvent(ctx context.Context, params VoidParams) error {
+	verr := &ValidationError{}
+	if params.EventID == "" && (params.MeterToken == "" || params.IdempotencyKey == "") {
+		verr.add("event_id", "or meter_token and idempotency_key are required")
+	}
+	if strings.TrimSpace(params.Reason) == "" {
+		verr.add("reason", "is required")
+	}
+	if len(verr.Errors) > 0 {
+		return verr
+	}
+	if !s.begin() {
+		return ErrClosed
+	}
+	defer s.ops.Done()
+
+	body := map[string]interface{}{
+		"reason":   params.Reason,
+		"metadata": correctionMetadata(nil, "void", params.Reason),
+	}
+	path := "/sdk/events/void"
+	if params.EventID != "" {
+		path = "/sdk/events/" + url.PathEscape(params.EventID) + "/void"
+	} else {
+		body["meter_token"] = params.MeterToken
+		body["idempotency_key"] = params.IdempotencyKey
+	}
+
+	err := s.doWithRetry(ctx, apiRequest{
+		method: "POST",
+		path:   path,
+		body:   body,
+		action: "void event",
+	}, nil)
+	return notFound(err, ErrEventNotFound)
+}
+
+func validateAdjustment(params AdjustmentParams) error {
+	verr := &ValidationError{}
+
+	if strings.TrimSpace(params.MeterToken) == "" {
+		verr.add("meter_token", "is required")
+	}
+	if strings.TrimSpace(params.CustomerExternalID) == "" {
+		verr.add("customer_external_id", "is required")
+	}
+	if strings.TrimSpace(params.Reason) == "" {
+		verr.add("reason", "is required")
+	}
+
+	if d := params.QuantityDecimal; d != nil {
+		if d.IsZero() {
+			verr.add("quantity", "must not be zero")
+		}
+	} else {
+		q := params.Quantity
+		switch {
+		case math.IsNaN(q) || math.IsInf(q, 0):
+			verr.add("quantity", "must be a finite number")
+		case q == 0:
+			verr.add("quantity", "must not be zero")
+		}
+	}
+
+	if len(verr.Errors) > 0 {
+		return verr
+	}
+	return nil
+}
+
+// correctionMetadata returns a copy of metadata tagged as a correction.
+func correctionMetadata(metadata map[string]interface{}, kind, reason string) map[string]interface{} {
+	tagged := make(map[string]interface{}, len(metadata)+2)
+	for k, v := range metadata {
+		tagged[k] = v
+	}
+	tagged[CorrectionKey] = kind
+	tagged[CorrectionReasonKey] = reason
+	return tagged
+}
+
+// notFound wraps a 404 *APIError in sentinel.
+func notFound(err, sentinel error) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %v", sentinel, err)
+	}
+	return err
+}
@@ -0,0 +1,251 @@
+package billing
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact, arbitrary-precision decimal number. Use it for
+// fractional quantities that must add up exactly, such as gigabytes or
+// currency amounts. The zero value is 0. Decimal values are immutable.
+type Decimal struct {
+	coef  *big.Int // unscaled value, nil means zero
+	scale int32    // number of digits after the decimal point, never negative
+}
+
+var bigTen = big.NewInt(10)
+
+// maxDecimalScale bounds the exponent and scale accepted by ParseDecimal,
+// so that untrusted input cannot make it allocate huge numbers.
+const maxDecimalScale = 1000
+
+// NewDecimal returns the decimal unscaled * 10^-scale. For example,
+// NewDecimal(1234, 2) is 12.34.
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	return makeDecimal(big.NewInt(unscaled), scale)
+}
+
+// DecimalFromFloat returns the shortest decimal that represents f exactly
+// when converted back to float64.
+func DecimalFromFloat(f float64) (Decimal, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Decimal{}, fmt.Errorf("Invalid decimal: %v is not a finite number", f)
+	}
+	return ParseDecimal(strconv.FormatFloat(f, 'g', -1, 64))
+}
+
+// ParseDecimal parses a decimal in plain ("-12.50") or exponent ("1.25e3")
+// notation. The resulting exponent must be within ±1000.
+func ParseDecimal(s string) (Decimal, error) {
+	in := s
+	var scale int64
+
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		exp, err := strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("Invalid decimal %q: bad exponent", in)
+		}
+		scale = -exp
+		s = s[:i]
+	}
+
+	sign := ""
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		sign, s = s[:1], s[1:]
+	}
+
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+	if intPart == "" && fracPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return Decimal{}, fmt.Errorf("Invalid decimal %q", in)
+	}
+
+	scale += int64(len(fracPart))
+	if scale > maxDecimalScale || scale < -maxDecimalScale {
+		return Decimal{}, fmt.Errorf("Invalid decimal %q: exponent out of range", in)
+	}
+
+	coef, ok := new(big.Int).SetString(sign+intPart+fracPart, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("Invalid decimal %q", in)
+	}
+	return makeDecimal(coef, int32(scale)), nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics if s is not a valid
+// decimal. It is intended for constants in code and tests.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// makeDecimal builds a Decimal, folding a negative scale into the
+// coefficient so that scale is never negative.
+func makeDecimal(coef *big.Int, scale int32) Decimal {
+	if scale < 0 {
+		coef = new(big.Int).Mul(coef, pow10(-scale))
+		scale = 0
+	}
+	return Decimal{coef: coef, scale: scale}
+}
+
+// mulRatio returns d * num / den, truncated to scale digits after the
+// decimal point. scale must not be smaller than d's scale.
+func (d Decimal) mulRatio(num, den int64, scale int32) Decimal {
+	coef := new(big.Int).Mul(d.rescale(scale), big.NewInt(num))
+	coef.Quo(coef, big.NewInt(den))
+	return Decimal{coef: coef, scale: scale}
+}
+
+// trimmed returns d without trailing fractional zeros.
+func (d Decimal) trimmed() Decimal {
+	coef, scale := new(big.Int).Set(d.unscaled()), d.scale
+	rem := new(big.Int)
+	for scale > 0 {
+		q, r := new(big.Int).QuoRem(coef, bigTen, rem)
+		if r.Sign() != 0 {
+			break
+		}
+		coef, scale = q, scale-1
+	}
+	return Decimal{coef: coef, scale: scale}
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+func (d Decimal) unscaled() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// rescale returns the coefficient of d expressed with the given, larger scale.
+func (d Decimal) rescale(scale int32) *big.Int {
+	if scale == d.scale {
+		return d.unscaled()
+	}
+	return new(big.Int).Mul(d.unscaled(), pow10(scale-d.scale))
+}
+
+func alignScales(a, b Decimal) (*big.Int, *big.Int, int32) {
+	scale := a.scale
+	if b.scale > scale {
+		scale = b.scale
+	}
+	return a.rescale(scale), b.rescale(scale), scale
+}
+
+// Add returns d + other.
+func (d Decimal) Add(other Decimal) Decimal {
+	x, y, scale := alignScales(d, other)
+	return Decimal{coef: new(big.Int).Add(x, y), scale: scale}
+}
+
+// Sub returns d - other.
+func (d Decimal) Sub(other Decimal) Decimal {
+	x, y, scale := alignScales(d, other)
+	return Decimal{coef: new(big.Int).Sub(x, y), scale: scale}
+}
+
+// Mul returns d * other.
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{
+		coef:  new(big.Int).Mul(d.unscaled(), other.unscaled()),
+		scale: d.scale + other.scale,
+	}
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: new(big.Int).Neg(d.unscaled()), scale: d.scale}
+}
+
+// Cmp compares d and other and returns -1, 0 or +1.
+func (d Decimal) Cmp(other Decimal) int {
+	x, y, _ := alignScales(d, other)
+	return x.Cmp(y)
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d.
+func (d Decimal) Sign() int {
+	return d.unscaled().Sign()
+}
+
+// IsZero reports whether d is zero.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// IsInteger reports whether d has no fractional part.
+func (d Decimal) IsInteger() bool {
+	if d.scale == 0 {
+		return true
+	}
+	return new(big.Int).Rem(d.unscaled(), pow10(d.scale)).Sign() == 0
+}
+
+// Float64 returns the float64 nearest to d.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String returns d in plain decimal notation, keeping trailing zeros.
+func (d Decimal) String() string {
+	digits := d.unscaled().String()
+	if d.scale == 0 {
+		return digits
+	}
+
+	sign := ""
+	if digits[0] == '-' {
+		sign, digits = "-", digits[1:]
+	}
+	if pad := int(d.scale) - len(digits) + 1; pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	point := len(digits) - int(d.scale)
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// MarshalJSON encodes d as a JSON number without losing precision.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number or a string holding a decimal.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
@@ -0,0 +1,160 @@
+package billing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Delivery tracks the delivery of a single event sent with TrackAsync.
+type Delivery struct {
+	id   string
+	done chan struct{}
+	resp *TrackEventResponse
+	err  error
+}
+
+func newDelivery(id string) *Delivery {
+	return &Delivery{id: id, done: make(chan struct{})}
+}
+
+// ID returns the event's idempotency key: the one given in
+// TrackEventParams, or one generated by TrackAsync.
+func (d *Delivery) ID() string {
+	return d.id
+}
+
+// Done returns a channel that is closed once the event has been delivered,
+// has failed for good, or was skipped by the customer filter or sampling.
+func (d *Delivery) Done() <-chan struct{} {
+	return d.done
+}
+
+// Wait blocks until the delivery is done or ctx is done. It returns the API
+// response, or nil for an event skipped by the customer filter or sampling.
+func (d *Delivery) Wait(ctx context.Context) (*TrackEventResponse, error) {
+	select {
+	case <-d.done:
+		return d.resp, d.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (d *Delivery) complete(resp *TrackEventResponse, err error) {
+	d.resp = resp
+	d.err = err
+	close(d.done)
+}
+
+// TrackAsync tracks an event like Track and returns a Delivery to wait for
+// its outcome. With batching enabled the event is still sent in a batch;
+// failed flushes that requeue the event keep the Delivery pending. Without
+// batching it is queued and sent in the background, bounded by ctx.
+//
+// If params has no IdempotencyKey, TrackAsync generates one, so a retried
+// delivery is never counted twice. Delivery.ID returns the key.
+func (s *SDK) TrackAsync(ctx context.Context, params TrackEventParams) *Delivery {
+	return s.trackAsync(ctx, params, nil)
+}
+
+// trackAsync is TrackAsync with the extra rule of a Meter.
+func (s *SDK) trackAsync(ctx context.Context, params TrackEventParams, rule *MeterRule) *Delivery {
+	if params.IdempotencyKey == "" {
+		params.IdempotencyKey = newEventID()
+	}
+	d := newDelivery(params.IdempotencyKey)
+
+	if !s.begin() {
+		d.complete(nil, ErrClosed)
+		return d
+	}
+
+	params, err := s.prepare(params, time.Now(), rule)
+	if err != nil {
+		s.ops.Done()
+		d.complete(nil, err)
+		return d
+	}
+
+	if !s.admit(&params) {
+		s.ops.Done()
+		d.complete(nil, nil)
+		return d
+	}
+	s.stats.tracked.Add(1)
+
+	if s.config.EnableBatching {
+		s.enqueue(queuedEvent{params: params, queuedAt: time.Now(), delivery: d})
+		s.ops.Done()
+		return d
+	}
+
+	// Shutdown waits for the send through ops
+	s.async.push(s, asyncEvent{ctx: ctx, params: params, delivery: d})
+	return d
+}
+
+// asyncEvent is an unbatched TrackAsync event waiting to be sent.
+type asyncEvent struct {
+	ctx      context.Context
+	params   TrackEventParams
+	delivery *Delivery
+}
+
+// asyncQueue holds the unbatched TrackAsync events. They are sent by at
+// most MaxConcurrentRequests goroutines, started as events arrive and
+// stopped once the queue is empty, rather than one goroutine per event.
+type asyncQueue struct {
+	mu      sync.Mutex
+	events  []asyncEvent
+	senders int
+}
+
+// push queues e and starts a sender if fewer than MaxConcurrentRequests
+// are running. The caller's ops reference is released once e is sent.
+func (q *asyncQueue) push(s *SDK, e asyncEvent) {
+	q.mu.Lock()
+	q.events = append(q.events, e)
+	start := q.senders < s.config.MaxConcurrentRequests
+	if start {
+		q.senders++
+	}
+	q.mu.Unlock()
+
+	if start {
+		go q.send(s)
+	}
+}
+
+// send sends queued events until the queue is empty.
+func (q *asyncQueue) send(s *SDK) {
+	for {
+		q.mu.Lock()
+		if len(q.events) == 0 {
+			q.senders--
+			q.events = nil
+			q.mu.Unlock()
+			return
+		}
+		e := q.events[0]
+		q.events[0] = asyncEvent{}
+		q.events = q.events[1:]
+		q.mu.Unlock()
+
+		e.delivery.complete(s.sendImmediate(e.ctx, e.params))
+		s.ops.Done()
+	}
+}
+
+// newEventID returns a random 128-bit ID in hex.
+func newEventID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// crypto/rand does not fail on supported platforms
+		panic("billing: failed to generate event ID: " + err.Error())
+	}
+	return hex.EncodeToString(b[:])
+}
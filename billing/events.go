@@ -0,0 +1,144 @@
+package billing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is a tracked event as stored by the API.
+type Event struct {
+	ID                 string                 `json:"id"`
+	CustomerID         string                 `json:"customer_id"`
+	CustomerExternalID string                 `json:"customer_external_id"`
+	MeterID            string                 `json:"meter_id"`
+	MeterToken         string                 `json:"meter_token"`
+	Quantity           string                 `json:"quantity"`
+	Timestamp          string                 `json:"timestamp"`
+	CreatedAt          string                 `json:"created_at"`
+	IdempotencyKey     string                 `json:"idempotency_key,omitempty"`
+	MetaData           map[string]interface{} `json:"meta_data,omitempty"`
+
+	// EventTime and CreatedTime hold Timestamp and CreatedAt parsed as UTC
+	// times. They are zero if the API returned a value that is not a timestamp.
+	EventTime   time.Time `json:"-"`
+	CreatedTime time.Time `json:"-"`
+}
+
+// UnmarshalJSON decodes the event and parses its time fields.
+func (e *Event) UnmarshalJSON(data []byte) error {
+	type rawEvent Event
+	if err := json.Unmarshal(data, (*rawEvent)(e)); err != nil {
+		return err
+	}
+	e.EventTime = parseAPITime(e.Timestamp)
+	e.CreatedTime = parseAPITime(e.CreatedAt)
+	return nil
+}
+
+// QuantityDecimal parses the quantity as an exact decimal.
+func (e *Event) QuantityDecimal() (Decimal, error) {
+	return ParseDecimal(e.Quantity)
+}
+
+// ListEventsParams filters the events returned by EventsClient.List. Zero
+// fields do not filter.
+type ListEventsParams struct {
+	CustomerExternalID string
+	MeterToken         string
+
+	// From (inclusive) and To (exclusive) bound the event timestamps
+	From time.Time
+	To   time.Time
+
+	// Metadata matches events whose metadata has all of the given values
+	Metadata map[string]string
+
+	// PageSize is the number of events fetched per request (default: 100)
+	PageSize int
+}
+
+// query encodes p as URL query parameters.
+func (p ListEventsParams) query() url.Values {
+	q := url.Values{}
+	if p.CustomerExternalID != "" {
+		q.Set("customer_external_id", p.CustomerExternalID)
+	}
+	if p.MeterToken != "" {
+		q.Set("meter_token", p.MeterToken)
+	}
+	if !p.From.IsZero() {
+		q.Set("from", p.From.UTC().Format(time.RFC3339Nano))
+	}
+	if !p.To.IsZero() {
+		q.Set("to", p.To.UTC().Format(time.RFC3339Nano))
+	}
+	for k, v := range p.Metadata {
+		q.Set("metadata["+k+"]", v)
+	}
+	pageSize := p.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	q.Set("limit", strconv.Itoa(pageSize))
+	return q
+}
+
+const defaultPageSize = 100
+
+// EventsClient looks up tracked events. Use it through SDK.Events.
+type EventsClient struct {
+	sdk *SDK
+}
+
+// Get returns the event with the given ID. An unknown ID is reported as
+// ErrEventNotFound.
+func (c *EventsClient) Get(ctx context.Context, id string) (*Event, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, fmt.Errorf("Invalid event ID: must not be empty")
+	}
+
+	var event Event
+	err := c.sdk.doWithRetry(ctx, apiRequest{
+		method: "GET",
+		path:   "/sdk/events/" + url.PathEscape(id),
+		action: "get event",
+	}, &event)
+	if err != nil {
+		return nil, notFound(err, ErrEventNotFound)
+	}
+	return &event, nil
+}
+
+// GetByIdempotencyKey returns the event tracked on meter with the given
+// idempotency key. An unknown event is reported as ErrEventNotFound.
+func (c *EventsClient) GetByIdempotencyKey(ctx context.Context, meterToken, key string) (*Event, error) {
+	if strings.TrimSpace(meterToken) == "" || strings.TrimSpace(key) == "" {
+		return nil, fmt.Errorf("Invalid event lookup: meter token and idempotency key are required")
+	}
+
+	q := url.Values{}
+	q.Set("meter_token", meterToken)
+	q.Set("idempotency_key", key)
+
+	var event Event
+	err := c.sdk.doWithRetry(ctx, apiRequest{
+		method: "GET",
+		path:   "/sdk/events/lookup?" + q.Encode(),
+		action: "get event",
+	}, &event)
+	if err != nil {
+		return nil, notFound(err, ErrEventNotFound)
+	}
+	return &event, nil
+}
+
+// List returns an iterator over the events matching params, oldest first.
+// Pages are fetched as the iterator advances.
+func (c *EventsClient) List(params ListEventsParams) *Iter[Event] {
+	return newIter[Event](c.sdk, "/sdk/events", params.query(), "list events")
+}
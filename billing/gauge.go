@@ -0,0 +1,360 @@
+package billing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// GaugeFunc returns the current value of a gauge for one customer, such as
+// the number of seats or the gigabytes stored.
+type GaugeFunc func(ctx context.Context) (float64, error)
+
+// Gauge samples per-customer values on a schedule and tracks their time
+// integral per bucket, e.g. GB-hours or seat-days. Each value is held until
+// the next sample. A bucket is tracked once it has ended, timestamped at its
+// start.
+//
+// The idempotency key of a bucket's event is derived from the meter, the
+// customer and the start of the time it covers, so retries and restarts
+// never report the same span twice. A bucket that fails transiently is
+// resent on the next sample, before any newer bucket, and by Stop.
+type Gauge struct {
+	sdk      *SDK
+	meter    *Meter
+	interval time.Duration
+	bucket   Period
+	unit     time.Duration
+
+	mu      sync.Mutex
+	series  map[string]*gaugeSeries
+	stop    chan struct{}
+	done    chan struct{}
+	stopped bool
+	stopMu  sync.Mutex // serializes Stop
+}
+
+// gaugeSeries is the integration state of one customer.
+type gaugeSeries struct {
+	mu      sync.Mutex
+	fn      GaugeFunc
+	removed bool
+	sampled bool
+	value   float64       // last sampled value
+	at      time.Time     // time up to which value has been integrated
+	from    time.Time     // start of the span covered by the open bucket
+	sum     float64       // integral of the open bucket in value-seconds
+	failed  []gaugeBucket // ended buckets to resend, oldest first
+}
+
+// gaugeBucket is the integral of an ended bucket.
+type gaugeBucket struct {
+	from time.Time // start of the span covered by the bucket
+	sum  float64   // integral in value-seconds
+}
+
+// GaugeOption configures a Gauge.
+type GaugeOption func(*Gauge)
+
+// WithSampleInterval sets how often the gauge is sampled (default: 1 minute).
+func WithSampleInterval(interval time.Duration) GaugeOption {
+	return func(g *Gauge) {
+		if interval > 0 {
+			g.interval = interval
+		}
+	}
+}
+
+// WithBucket sets the buckets the integral is tracked in, e.g. DailyPeriod
+// (default: HourlyPeriod).
+func WithBucket(period Period) GaugeOption {
+	return func(g *Gauge) {
+		if period != nil {
+			g.bucket = period
+		}
+	}
+}
+
+// WithGaugeUnit sets the time unit of the integral, e.g. 24*time.Hour for
+// seat-days (default: time.Hour).
+func WithGaugeUnit(unit time.Duration) GaugeOption {
+	return func(g *Gauge) {
+		if unit > 0 {
+			g.unit = unit
+		}
+	}
+}
+
+// NewGauge starts a gauge that tracks time-integrated values on meter. Add
+// customers with Observe, and call Stop before shutting down the SDK to
+// track the open buckets. A gauge created after Shutdown tracks nothing.
+func (s *SDK) NewGauge(meter *Meter, opts ...GaugeOption) *Gauge {
+	g := &Gauge{
+		sdk:      s,
+		meter:    meter,
+		interval: time.Minute,
+		bucket:   HourlyPeriod,
+		unit:     time.Hour,
+		series:   make(map[string]*gaugeSeries),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(g)
+		}
+	}
+
+	// After Shutdown the gauge never samples
+	if !s.spawn() {
+		close(g.done)
+		return g
+	}
+	go g.run()
+	return g
+}
+
+// Observe starts sampling fn for customer, replacing an earlier function
+// for the same customer.
+func (g *Gauge) Observe(customer string, fn GaugeFunc) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if series, ok := g.series[customer]; ok {
+		series.mu.Lock()
+		series.fn = fn
+		series.mu.Unlock()
+		return
+	}
+	g.series[customer] = &gaugeSeries{fn: fn}
+}
+
+// Remove stops sampling customer and tracks its open bucket.
+func (g *Gauge) Remove(ctx context.Context, customer string) error {
+	g.mu.Lock()
+	series, ok := g.series[customer]
+	delete(g.series, customer)
+	g.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	return g.close(ctx, customer, series, time.Now().Round(0))
+}
+
+// Stop stops sampling and tracks the open bucket of every customer.
+// Calling Stop again resends the buckets that failed transiently and has
+// no effect otherwise.
+func (g *Gauge) Stop(ctx context.Context) error {
+	g.stopMu.Lock()
+	defer g.stopMu.Unlock()
+
+	g.mu.Lock()
+	stopped := g.stopped
+	g.stopped = true
+	g.mu.Unlock()
+
+	if !stopped {
+		close(g.stop)
+		<-g.done
+	}
+
+	g.mu.Lock()
+	series := g.series
+	g.series = make(map[string]*gaugeSeries)
+	g.mu.Unlock()
+
+	now := time.Now().Round(0)
+	var errs []error
+	for customer, s := range series {
+		if stopped {
+			s.mu.Lock()
+			errs = append(errs, g.resend(ctx, customer, s))
+			s.mu.Unlock()
+		} else {
+			errs = append(errs, g.close(ctx, customer, s, now))
+		}
+	}
+
+	// Keep the series with buckets left to resend for the next Stop
+	g.mu.Lock()
+	for customer, s := range series {
+		s.mu.Lock()
+		if len(s.failed) > 0 {
+			g.series[customer] = s
+		}
+		s.mu.Unlock()
+	}
+	g.mu.Unlock()
+	return errors.Join(errs...)
+}
+
+func (g *Gauge) run() {
+	defer g.sdk.wg.Done()
+	defer close(g.done)
+
+	g.sample(time.Now())
+
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			g.sample(now)
+		case <-g.stop:
+			return
+		case <-g.sdk.stopChan:
+			return
+		}
+	}
+}
+
+// sample integrates every series up to now, tracks the buckets that ended
+// and takes a new sample. A failed sample keeps the previous value.
+func (g *Gauge) sample(now time.Time) {
+	// Bucket bounds carry no monotonic clock reading; without one, every
+	// time difference uses the wall clock and the pieces of a bucket add up
+	now = now.Round(0)
+
+	g.mu.Lock()
+	series := make(map[string]*gaugeSeries, len(g.series))
+	for customer, s := range g.series {
+		series[customer] = s
+	}
+	g.mu.Unlock()
+
+	for customer, s := range series {
+		g.sampleSeries(customer, s, now)
+	}
+}
+
+func (g *Gauge) sampleSeries(customer string, s *gaugeSeries, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.removed {
+		return
+	}
+	if err := g.resend(context.Background(), customer, s); err != nil {
+		g.sdk.log("Failed to resend gauge %s for %s: %v", g.meter.token, customer, err)
+	}
+	g.advance(context.Background(), customer, s, now)
+
+	ctx, cancel := context.WithTimeout(context.Background(), g.interval)
+	value, err := s.fn(ctx)
+	cancel()
+	if err != nil {
+		g.sdk.log("Failed to sample gauge %s for %s: %v", g.meter.token, customer, err)
+		return
+	}
+	if !s.sampled {
+		s.sampled = true
+		s.at, s.from = now, now
+	}
+	s.value = value
+}
+
+// advance integrates the held value of s up to now, tracking every bucket
+// that ends on the way. now must have no monotonic clock reading, like the
+// bucket bounds. The caller must hold s.mu.
+func (g *Gauge) advance(ctx context.Context, customer string, s *gaugeSeries, now time.Time) {
+	for s.sampled && s.at.Before(now) {
+		_, end := g.bucket.Bounds(s.at)
+		to := now
+		if !end.After(now) {
+			to = end
+		}
+		s.sum += s.value * to.Sub(s.at).Seconds()
+		s.at = to
+
+		if to.Equal(end) {
+			b := gaugeBucket{from: s.from, sum: s.sum}
+			s.from, s.sum = end, 0
+			if err := g.emit(ctx, customer, s, b); err != nil {
+				g.sdk.log("Failed to track gauge %s for %s: %v", g.meter.token, customer, err)
+			}
+		}
+	}
+}
+
+// close resends the failed buckets of s, integrates it up to now and tracks
+// its open bucket.
+func (g *Gauge) close(ctx context.Context, customer string, s *gaugeSeries, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.removed {
+		return nil
+	}
+	s.removed = true
+	err := g.resend(ctx, customer, s)
+	g.advance(ctx, customer, s, now)
+	if !s.sampled {
+		return err
+	}
+	return errors.Join(err, g.emit(ctx, customer, s, gaugeBucket{from: s.from, sum: s.sum}))
+}
+
+// emit tracks bucket b of s, unless its integral is zero. While older
+// buckets wait to be resent, b is queued behind them; a bucket that fails
+// transiently is kept to be resent. The caller must hold s.mu.
+func (g *Gauge) emit(ctx context.Context, customer string, s *gaugeSeries, b gaugeBucket) error {
+	if b.sum == 0 {
+		return nil
+	}
+	if len(s.failed) > 0 {
+		s.failed = append(s.failed, b)
+		return nil
+	}
+
+	err := g.send(ctx, customer, b)
+	if err != nil && isTransient(err) {
+		s.failed = append(s.failed, b)
+	}
+	return err
+}
+
+// resend tracks the failed buckets of s, oldest first, and stops at the
+// first one that fails transiently. The caller must hold s.mu.
+func (g *Gauge) resend(ctx context.Context, customer string, s *gaugeSeries) error {
+	for len(s.failed) > 0 {
+		err := g.send(ctx, customer, s.failed[0])
+		if err != nil && isTransient(err) {
+			return err
+		}
+		s.failed = s.failed[1:]
+		if err != nil {
+			return err
+		}
+	}
+	s.failed = nil
+	return nil
+}
+
+// send tracks bucket b as one event.
+func (g *Gauge) send(ctx context.Context, customer string, b gaugeBucket) error {
+	ts := b.from
+	params := g.meter.params(customer, b.sum/g.unit.Seconds(), nil)
+	params.Timestamp = &ts
+	params.IdempotencyKey = gaugeKey(g.meter.token, customer, b.from)
+
+	_, err := g.sdk.track(ctx, params, g.meter.rule)
+	return err
+}
+
+// gaugeKey derives the idempotency key of a bucket from the meter, the
+// customer and the start of the span it covers.
+func gaugeKey(meter, customer string, from time.Time) string {
+	h := sha256.New()
+	h.Write([]byte(meter))
+	h.Write([]byte{0})
+	h.Write([]byte(customer))
+	h.Write([]byte{0})
+	h.Write([]byte(strconv.FormatInt(from.UnixNano(), 10)))
+	return "gauge-" + hex.EncodeToString(h.Sum(nil)[:16])
+}
@@ -0,0 +1,126 @@
+package billing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrUnauthorized is returned by Preflight when the API key is rejected.
+	ErrUnauthorized = errors.New("API key is not authorized")
+
+	// ErrMeterNotFound is reported by Preflight for meters that do not exist.
+	ErrMeterNotFound = errors.New("Meter not found")
+)
+
+// PreflightError is returned by Preflight when one or more meters could not
+// be verified.
+type PreflightError struct {
+	// Meters maps each failing meter token to the problem found
+	Meters map[string]error
+}
+
+func (e *PreflightError) Error() string {
+	tokens := make([]string, 0, len(e.Meters))
+	for token := range e.Meters {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+
+	problems := make([]string, len(tokens))
+	for i, token := range tokens {
+		problems[i] = fmt.Sprintf("%s: %v", token, e.Meters[token])
+	}
+	return "Preflight failed: " + strings.Join(problems, "; ")
+}
+
+// Ping checks that the Fluxrate API is reachable and healthy by calling its
+// /health endpoint. It does not check the API key and bypasses the circuit
+// breaker.
+func (s *SDK) Ping(ctx context.Context) error {
+	healthURL, err := s.healthURL()
+	if err != nil {
+		return err
+	}
+
+	return s.do(ctx, apiRequest{
+		method: "GET",
+		url:    healthURL,
+		action: "ping API",
+		probe:  true,
+	}, nil)
+}
+
+// Preflight verifies that the API key is authorized and that every given
+// meter exists. It is meant for readiness probes, so that a revoked key or a
+// wrong APIUrl fails at startup instead of in background flushes.
+//
+// A rejected key is reported as ErrUnauthorized; missing or unverifiable
+// meters are reported together in a *PreflightError.
+func (s *SDK) Preflight(ctx context.Context, meterTokens ...string) error {
+	err := s.do(ctx, apiRequest{
+		method: "GET",
+		path:   "/sdk/verify",
+		action: "verify API key",
+		probe:  true,
+	}, nil)
+	if err != nil {
+		if isUnauthorized(err) {
+			return fmt.Errorf("%w: %v", ErrUnauthorized, err)
+		}
+		return err
+	}
+
+	failed := make(map[string]error)
+	for _, token := range meterTokens {
+		err := s.do(ctx, apiRequest{
+			method: "GET",
+			path:   "/sdk/meters/" + url.PathEscape(token),
+			action: "verify meter",
+			probe:  true,
+		}, nil)
+		if err == nil {
+			continue
+		}
+
+		if isUnauthorized(err) {
+			return fmt.Errorf("%w: %v", ErrUnauthorized, err)
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			err = fmt.Errorf("%w: %v", ErrMeterNotFound, err)
+		}
+		failed[token] = err
+	}
+
+	if len(failed) > 0 {
+		return &PreflightError{Meters: failed}
+	}
+	return nil
+}
+
+func isUnauthorized(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) &&
+		(apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden)
+}
+
+// healthURL returns the /health endpoint at the root of the API host.
+func (s *SDK) healthURL() (string, error) {
+	u, err := url.Parse(s.config.APIUrl)
+	if err != nil {
+		return "", fmt.Errorf("Invalid API URL: %w", err)
+	}
+	u.Path = "/health"
+	u.RawQuery = ""
+	return u.String(), nil
+}
@@ -0,0 +1,131 @@
+package billing
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// Iter iterates over the results of a list endpoint, fetching pages as it
+// advances:
+//
+//	it := sdk.Events.List(params)
+//	for it.Next(ctx) {
+//		event := it.Item()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// Pages follow the next_cursor returned by the server or, for endpoints
+// paged by offset, advance the offset while the server reports has_more.
+// Each page request is retried with the SDK's retry policy. An Iter is not
+// safe for concurrent use.
+type Iter[T any] struct {
+	sdk    *SDK
+	path   string
+	query  url.Values
+	action string
+
+	page    []T
+	index   int
+	cursor  string
+	offset  int
+	done    bool
+	current T
+	err     error
+}
+
+// listPage is a page of a list endpoint.
+type listPage[T any] struct {
+	Data       []T    `json:"data"`
+	NextCursor string `json:"next_cursor"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// newIter returns an Iter over the list endpoint at path. query holds the
+// endpoint's filters and page size; action is used in error messages.
+func newIter[T any](sdk *SDK, path string, query url.Values, action string) *Iter[T] {
+	return &Iter[T]{sdk: sdk, path: path, query: query, action: action}
+}
+
+// Next advances to the next item, fetching the next page if needed. It
+// returns false when there are no more items, an error occurred or ctx is
+// done.
+func (it *Iter[T]) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	for it.index >= len(it.page) {
+		if it.done {
+			return false
+		}
+		if !it.fetch(ctx) {
+			return false
+		}
+	}
+
+	it.current = it.page[it.index]
+	it.index++
+	return true
+}
+
+// Item returns the current item.
+func (it *Iter[T]) Item() T {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iter[T]) Err() error {
+	return it.err
+}
+
+// Collect returns up to limit of the remaining items (all of them if limit
+// is 0). On error it returns the items collected so far with the error.
+func (it *Iter[T]) Collect(ctx context.Context, limit int) ([]T, error) {
+	var items []T
+	for (limit <= 0 || len(items) < limit) && it.Next(ctx) {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
+func (it *Iter[T]) fetch(ctx context.Context) bool {
+	q := url.Values{}
+	for k, v := range it.query {
+		q[k] = v
+	}
+	switch {
+	case it.cursor != "":
+		q.Set("cursor", it.cursor)
+	case it.offset > 0:
+		q.Set("offset", strconv.Itoa(it.offset))
+	}
+
+	var page listPage[T]
+	err := it.sdk.doWithRetry(ctx, apiRequest{
+		method: "GET",
+		path:   it.path + "?" + q.Encode(),
+		action: it.action,
+	}, &page)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	it.page, it.index = page.Data, 0
+	switch {
+	case page.NextCursor != "":
+		it.cursor = page.NextCursor
+	case page.HasMore && len(page.Data) > 0 && it.cursor == "":
+		it.offset += len(page.Data)
+	default:
+		it.done = true
+	}
+	return true
+}
@@ -0,0 +1,31 @@
+//go:build go1.23
+
+package billing
+
+import (
+	"context"
+	"iter"
+)
+
+// All returns the remaining items as an iter.Seq2 for use with range:
+//
+//	for event, err := range sdk.Events.List(params).All(ctx) {
+//		if err != nil {
+//			...
+//		}
+//	}
+//
+// An error is yielded once, with the zero value, and ends the sequence.
+func (it *Iter[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for it.Next(ctx) {
+			if !yield(it.Item(), nil) {
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
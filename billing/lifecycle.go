@@ -0,0 +1,159 @@
+package billing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrClosed is returned by Track, TrackImmediate and Flush after Shutdown
+// has been called.
+var ErrClosed = errors.New("SDK is shut down")
+
+// ShutdownError is returned by Shutdown when events could not be delivered
+// before ctx was done or failed transiently in the last flush. Unsent holds
+// the events so the caller can persist or resend them. Events cut off
+// mid-request may have reached the API; resend them with their
+// IdempotencyKey to avoid duplicates.
+type ShutdownError struct {
+	Unsent []TrackEventParams
+	Err    error
+}
+
+func (e *ShutdownError) Error() string {
+	return fmt.Sprintf("Failed to send %d events before shutdown: %v", len(e.Unsent), e.Err)
+}
+
+func (e *ShutdownError) Unwrap() error {
+	return e.Err
+}
+
+// begin registers a Track, TrackImmediate or Flush call so that Shutdown
+// waits for it. It returns false once the SDK is closed; otherwise the
+// caller must call s.ops.Done when finished.
+func (s *SDK) begin() bool {
+	s.lifecycleMu.RLock()
+	defer s.lifecycleMu.RUnlock()
+
+	if s.closed {
+		return false
+	}
+	s.ops.Add(1)
+	return true
+}
+
+// spawn registers a background goroutine in s.wg so that Shutdown waits for
+// it. It returns false once the SDK is closed, when s.wg must not grow.
+func (s *SDK) spawn() bool {
+	s.lifecycleMu.RLock()
+	defer s.lifecycleMu.RUnlock()
+
+	if s.closed {
+		return false
+	}
+	s.wg.Add(1)
+	return true
+}
+
+// Shutdown stops background work, waits for in-flight calls and flushes
+// pending events. It may be called more than once; later calls wait for the
+// first one to finish and return nil.
+//
+// If ctx is done before every event is sent, or the last flush fails
+// transiently, Shutdown returns a *ShutdownError listing the unsent events.
+func (s *SDK) Shutdown(ctx context.Context) error {
+	first := false
+	s.shutdownOnce.Do(func() { first = true })
+	if !first {
+		select {
+		case <-s.shutdownDone:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	defer close(s.shutdownDone)
+
+	s.log("Shutting down SDK...")
+
+	// Reject new calls; those already running are counted in ops
+	s.lifecycleMu.Lock()
+	s.closed = true
+	s.lifecycleMu.Unlock()
+
+	// Signal stop to background goroutines
+	close(s.stopChan)
+
+	// Wait for the batch timer and in-flight calls
+	idle := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		s.ops.Wait()
+		close(idle)
+	}()
+
+	select {
+	case <-idle:
+	case <-ctx.Done():
+		// Flushes still running are left to finish on their own; unsent
+		// takes over the events they hold
+		go s.pool.stop()
+		return s.unsent(ctx.Err())
+	}
+
+	// Flush remaining events; those that fail transiently are requeued
+	if s.queue.len() > 0 {
+		if _, err := s.flushBatch(ctx, 0); err != nil {
+			return fmt.Errorf("Failed to flush remaining events: %w", err)
+		}
+	}
+
+	// Stop the workers once the last flush has completed
+	s.pool.stop()
+
+	if err := s.unsent(ctx.Err()); err != nil {
+		return err
+	}
+
+	s.log("SDK shutdown complete")
+	return nil
+}
+
+// unsent collects the events left in the queue or held by running flushes
+// into a *ShutdownError and completes their deliveries. If err is nil, the
+// last request error explains why they were not sent. It returns nil if
+// there are no such events.
+func (s *SDK) unsent(err error) error {
+	queued := s.flushing.abandon(s.queue)
+	if len(queued) == 0 {
+		return nil
+	}
+
+	events := make([]TrackEventParams, len(queued))
+	for i, e := range queued {
+		events[i] = e.params
+	}
+	if err == nil {
+		err = s.Stats().LastError
+		if s.breaker.current() == CircuitOpen {
+			err = ErrCircuitOpen
+		}
+	}
+	if err == nil {
+		err = errors.New("Unknown error")
+	}
+	for _, e := range queued {
+		if e.delivery != nil {
+			e.delivery.complete(nil, err)
+		}
+	}
+
+	s.log("Shutdown left %d events unsent: %v", len(events), err)
+	return &ShutdownError{Unsent: events, Err: err}
+}
+
+// isCancellation reports whether err was caused by a cancelled or expired
+// context rather than by the API.
+func isCancellation(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,109 @@
+package billing
+
+import "context"
+
+// ReadingKey is the metadata key that marks an event sent by Meter.Set as a
+// reading of the meter's current value rather than an amount of usage.
+const ReadingKey = "reading"
+
+// Attr is a metadata key-value pair attached to a single event.
+type Attr struct {
+	Key   string
+	Value interface{}
+}
+
+// Meter is a handle for tracking events of one meter. It is created with
+// SDK.Meter, never changes afterwards and is safe for concurrent use, so it
+// can be stored in a package-level variable once the SDK is set up.
+type Meter struct {
+	sdk      *SDK
+	token    string
+	metadata map[string]interface{}
+	rule     *MeterRule
+}
+
+// MeterOption configures a Meter.
+type MeterOption func(*Meter)
+
+// WithDefaultMetadata adds metadata to every event of the meter. Attrs
+// passed to Add or Set take precedence.
+func WithDefaultMetadata(metadata map[string]interface{}) MeterOption {
+	return func(m *Meter) {
+		for k, v := range metadata {
+			m.metadata[k] = v
+		}
+	}
+}
+
+// WithRule validates the events of the meter against rule, in addition to
+// any rule configured with WithMeterRule.
+func WithRule(rule MeterRule) MeterOption {
+	return func(m *Meter) {
+		m.rule = &rule
+	}
+}
+
+// Meter returns a handle for tracking events of the meter with the given
+// token.
+func (s *SDK) Meter(token string, opts ...MeterOption) *Meter {
+	m := &Meter{
+		sdk:      s,
+		token:    token,
+		metadata: make(map[string]interface{}),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(m)
+		}
+	}
+	return m
+}
+
+// Token returns the meter token.
+func (m *Meter) Token() string {
+	return m.token
+}
+
+// Add tracks quantity units of usage for customer, like Track. With
+// batching enabled the event is queued.
+func (m *Meter) Add(ctx context.Context, customer string, quantity float64, attrs ...Attr) error {
+	_, err := m.sdk.track(ctx, m.params(customer, quantity, attrs), m.rule)
+	return err
+}
+
+// Set records the current value of a meter for customer, such as the
+// number of seats or the storage in use. The event carries the value as its
+// quantity and is marked with ReadingKey, so that a meter aggregating the
+// last or maximum value replaces earlier readings instead of adding them up.
+// Sampling never scales a reading. To bill a value over time, such as
+// GB-hours or seat-days, use a Gauge instead.
+func (m *Meter) Set(ctx context.Context, customer string, value float64, attrs ...Attr) error {
+	params := m.params(customer, value, attrs)
+	metadata := make(map[string]interface{}, len(params.Metadata)+1)
+	for k, v := range params.Metadata {
+		metadata[k] = v
+	}
+	metadata[ReadingKey] = true
+	params.Metadata = metadata
+
+	_, err := m.sdk.track(ctx, params, m.rule)
+	return err
+}
+
+func (m *Meter) params(customer string, quantity float64, attrs []Attr) TrackEventParams {
+	params := TrackEventParams{
+		MeterToken:         m.token,
+		CustomerExternalID: customer,
+		Quantity:           quantity,
+	}
+	if len(m.metadata) > 0 || len(attrs) > 0 {
+		params.Metadata = make(map[string]interface{}, len(m.metadata)+len(attrs))
+		for k, v := range m.metadata {
+			params.Metadata[k] = v
+		}
+		for _, attr := range attrs {
+			params.Metadata[attr.Key] = attr.Value
+		}
+	}
+	return params
+}
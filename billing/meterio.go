@@ -0,0 +1,320 @@
+package billing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ByteUnit is the unit in which MeterReader and MeterWriter report byte
+// counts.
+type ByteUnit int
+
+const (
+	Bytes ByteUnit = iota // 1 byte
+	KB                    // 1000 bytes
+	MB                    // 1000^2 bytes
+	GB                    // 1000^3 bytes
+	KiB                   // 1024 bytes
+	MiB                   // 1024^2 bytes
+	GiB                   // 1024^3 bytes
+)
+
+func (u ByteUnit) String() string {
+	switch u {
+	case Bytes:
+		return "B"
+	case KB:
+		return "KB"
+	case MB:
+		return "MB"
+	case GB:
+		return "GB"
+	case KiB:
+		return "KiB"
+	case MiB:
+		return "MiB"
+	case GiB:
+		return "GiB"
+	}
+	return "unknown"
+}
+
+// Convert returns n bytes expressed in u, exactly.
+func (u ByteUnit) Convert(n int64) Decimal {
+	switch u {
+	case KB:
+		return NewDecimal(n, 3).trimmed()
+	case MB:
+		return NewDecimal(n, 6).trimmed()
+	case GB:
+		return NewDecimal(n, 9).trimmed()
+	case KiB, MiB, GiB:
+		// n / 2^k is exactly n * 5^k / 10^k
+		k := int64(10 * (u - KiB + 1))
+		coef := new(big.Int).Exp(big.NewInt(5), big.NewInt(k), nil)
+		coef.Mul(coef, big.NewInt(n))
+		return makeDecimal(coef, int32(k)).trimmed()
+	}
+	return NewDecimal(n, 0)
+}
+
+// MeterIOOption configures MeterReader and MeterWriter.
+type MeterIOOption func(*byteCounter)
+
+// WithByteUnit sets the unit of the tracked quantity (default: Bytes).
+func WithByteUnit(unit ByteUnit) MeterIOOption {
+	return func(c *byteCounter) {
+		c.unit = unit
+	}
+}
+
+// WithReportEvery tracks the bytes counted so far whenever at least n more
+// bytes have passed. The report is sent in the background; bytes counted
+// while it runs go into the next one. Without it, bytes are tracked on
+// Close.
+func WithReportEvery(n int64) MeterIOOption {
+	return func(c *byteCounter) {
+		c.every = n
+	}
+}
+
+// WithReportInterval tracks the bytes counted so far when interval has
+// passed since the last report. The interval is checked on every Read or
+// Write, so an idle stream reports on Close.
+func WithReportInterval(interval time.Duration) MeterIOOption {
+	return func(c *byteCounter) {
+		c.interval = interval
+	}
+}
+
+// byteCounter counts bytes and tracks them on meter. Reports use the
+// idempotency key "<id>-<seq>" and, like Timer's, a report that fails
+// transiently is resent unchanged before newer bytes are reported.
+// Reports due on Read or Write are sent in the background, one at a time,
+// so they never block the stream; only close reports synchronously.
+type byteCounter struct {
+	meter    *Meter
+	customer string
+	unit     ByteUnit
+	every    int64
+	interval time.Duration
+	id       string
+
+	mu         sync.Mutex
+	total      int64
+	pending    int64
+	lastReport time.Time
+	seq        int
+	failed     *byteReport   // last report, if it failed transiently
+	sending    chan struct{} // closed when the background report ends
+	closed     bool
+}
+
+// byteReport is a number of bytes reported under one idempotency key.
+type byteReport struct {
+	key   string
+	bytes int64
+}
+
+func newByteCounter(meter *Meter, customer string, opts []MeterIOOption) *byteCounter {
+	c := &byteCounter{
+		meter:      meter,
+		customer:   customer,
+		id:         newEventID(),
+		lastReport: time.Now(),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(c)
+		}
+	}
+	return c
+}
+
+func (c *byteCounter) add(n int) {
+	if n <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.total += int64(n)
+	c.pending += int64(n)
+	due := (c.every > 0 && c.pending >= c.every) ||
+		(c.interval > 0 && time.Since(c.lastReport) >= c.interval)
+
+	// While a report is running, the bytes wait for the next one. After
+	// Shutdown they wait for Close, which returns ErrClosed.
+	if !due || c.closed || c.sending != nil || !c.meter.sdk.spawn() {
+		return
+	}
+	c.sending = make(chan struct{})
+	go c.reportInBackground(c.sending)
+}
+
+// reportInBackground reports the pending bytes and closes done.
+func (c *byteCounter) reportInBackground(done chan struct{}) {
+	defer c.meter.sdk.wg.Done()
+
+	// A failed report is resent by the next one; Close returns the error
+	if err := c.report(context.Background()); err != nil {
+		c.meter.sdk.log("Failed to report bytes for meter %s: %v", c.meter.token, err)
+	}
+
+	c.mu.Lock()
+	c.sending = nil
+	c.mu.Unlock()
+	close(done)
+}
+
+func (c *byteCounter) count() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.total
+}
+
+// report resends a report that failed transiently, then tracks the pending
+// bytes as one event under the next sequence number.
+func (c *byteCounter) report(ctx context.Context) error {
+	c.mu.Lock()
+	retry := c.failed
+	c.failed = nil
+	c.mu.Unlock()
+
+	if retry != nil {
+		if err := c.send(ctx, *retry); err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	if c.pending == 0 {
+		c.mu.Unlock()
+		return nil
+	}
+	c.seq++
+	r := byteReport{key: c.id + "-" + strconv.Itoa(c.seq), bytes: c.pending}
+	c.pending = 0
+	c.lastReport = time.Now()
+	c.mu.Unlock()
+
+	return c.send(ctx, r)
+}
+
+// send tracks r. If it fails transiently, r is kept to be resent unchanged,
+// as the event may have reached the API.
+func (c *byteCounter) send(ctx context.Context, r byteReport) error {
+	quantity := c.unit.Convert(r.bytes)
+	params := c.meter.params(c.customer, 0, nil)
+	params.QuantityDecimal = &quantity
+	params.IdempotencyKey = r.key
+
+	_, err := c.meter.sdk.track(ctx, params, c.meter.rule)
+	if err != nil && isTransient(err) {
+		c.mu.Lock()
+		c.failed = &r
+		c.mu.Unlock()
+	}
+	return err
+}
+
+// close waits for a background report, reports the pending bytes once and
+// closes closer if it is set. Later calls only resend a report that failed
+// transiently.
+func (c *byteCounter) close(closer interface{}) error {
+	c.mu.Lock()
+	first := !c.closed
+	c.closed = true // add starts no more background reports
+	sending := c.sending
+	c.mu.Unlock()
+
+	if sending != nil {
+		<-sending
+	}
+
+	if !first {
+		c.mu.Lock()
+		failed := c.failed != nil
+		c.mu.Unlock()
+		if failed {
+			return c.report(context.Background())
+		}
+		return nil
+	}
+
+	err := c.report(context.Background())
+	if cl, ok := closer.(io.Closer); ok {
+		err = errors.Join(err, cl.Close())
+	}
+	return err
+}
+
+// MeteredReader is an io.ReadCloser that counts the bytes read through it
+// and tracks them as usage. It is created with MeterReader.
+type MeteredReader struct {
+	r io.Reader
+	c *byteCounter
+}
+
+// MeterReader wraps r so that the bytes read from it are tracked on meter
+// for customer. The bytes are tracked on Close, and more often if
+// WithReportEvery or WithReportInterval is given.
+func MeterReader(r io.Reader, meter *Meter, customer string, opts ...MeterIOOption) *MeteredReader {
+	return &MeteredReader{r: r, c: newByteCounter(meter, customer, opts)}
+}
+
+func (m *MeteredReader) Read(p []byte) (int, error) {
+	n, err := m.r.Read(p)
+	m.c.add(n)
+	return n, err
+}
+
+// Count returns the number of bytes read so far.
+func (m *MeteredReader) Count() int64 {
+	return m.c.count()
+}
+
+// Close tracks the bytes not reported yet and closes the underlying reader
+// if it is an io.Closer. Calling Close again retries a report that failed
+// transiently and has no effect otherwise.
+func (m *MeteredReader) Close() error {
+	return m.c.close(m.r)
+}
+
+// MeteredWriter is an io.WriteCloser that counts the bytes written through
+// it and tracks them as usage. It is created with MeterWriter.
+type MeteredWriter struct {
+	w io.Writer
+	c *byteCounter
+}
+
+// MeterWriter wraps w so that the bytes written to it are tracked on meter
+// for customer. The bytes are tracked on Close, and more often if
+// WithReportEvery or WithReportInterval is given.
+func MeterWriter(w io.Writer, meter *Meter, customer string, opts ...MeterIOOption) *MeteredWriter {
+	return &MeteredWriter{w: w, c: newByteCounter(meter, customer, opts)}
+}
+
+func (m *MeteredWriter) Write(p []byte) (int, error) {
+	n, err := m.w.Write(p)
+	m.c.add(n)
+	return n, err
+}
+
+// Count returns the number of bytes written so far.
+func (m *MeteredWriter) Count() int64 {
+	return m.c.count()
+}
+
+// Close tracks the bytes not reported yet and closes the underlying writer
+// if it is an io.Closer. Calling Close again retries a report that failed
+// transiently and has no effect otherwise.
+func (m *MeteredWriter) Close() error {
+	return m.c.close(m.w)
+}
@@ -0,0 +1,249 @@
+package billing
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	defaultAPIUrl         = "https://api.fluxrate.co/api/v1"
+	defaultBatchSize      = 100
+	defaultBatchInterval  = 5 * time.Second
+	defaultMaxRetries     = 10
+	defaultInitialBackoff = 1 * time.Second
+	defaultMaxBackoff     = 10 * time.Second
+)
+
+// Option configures an SDK created with New.
+type Option func(*settings)
+
+// settings is the configuration assembled from a list of options. Besides
+// the plain Config it records which settings were given explicitly, so that
+// "turned off" can be told apart from "not set".
+type settings struct {
+	config      Config
+	batchingSet bool
+	retry       *RetryPolicy
+}
+
+// RetryPolicy controls how failed requests are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values below 1 are treated as 1, which disables retries.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry (default: 1 second)
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the exponential backoff delay (default: 10 seconds)
+	MaxBackoff time.Duration
+}
+
+// NoRetry is a RetryPolicy that sends each request exactly once.
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    defaultMaxRetries,
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+	}
+}
+
+func (p RetryPolicy) normalize() RetryPolicy {
+	if p.MaxAttempts < 1 {
+		p.MaxAttempts = 1
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = defaultInitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaultMaxBackoff
+	}
+	return p
+}
+
+// backoff returns the delay to wait after the given failed attempt.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.InitialBackoff
+	for i := 1; i < attempt && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	return delay
+}
+
+// WithAPIURL sets the API base URL (default: https://api.fluxrate.co/api/v1).
+func WithAPIURL(url string) Option {
+	return func(s *settings) {
+		s.config.APIUrl = url
+	}
+}
+
+// WithBatching enables batching with the given batch size and flush
+// interval. A size or interval of zero keeps the default value.
+func WithBatching(size int, interval time.Duration) Option {
+	return func(s *settings) {
+		s.batchingSet = true
+		s.config.EnableBatching = true
+		s.config.BatchSize = size
+		s.config.BatchInterval = interval
+	}
+}
+
+// WithoutBatching disables batching, so Track sends every event immediately.
+func WithoutBatching() Option {
+	return func(s *settings) {
+		s.batchingSet = true
+		s.config.EnableBatching = false
+	}
+}
+
+// WithRetry sets the retry policy. Use NoRetry to disable retries.
+func WithRetry(policy RetryPolicy) Option {
+	return func(s *settings) {
+		s.retry = &policy
+	}
+}
+
+// WithMaxConcurrentRequests limits the number of API requests in flight.
+func WithMaxConcurrentRequests(n int) Option {
+	return func(s *settings) {
+		s.config.MaxConcurrentRequests = n
+	}
+}
+
+// WithRateLimit limits outbound API requests to requestsPerSecond, allowing
+// bursts of up to burst requests.
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(s *settings) {
+		s.config.RateLimit = RateLimit{RequestsPerSecond: requestsPerSecond, Burst: burst}
+	}
+}
+
+// WithCircuitBreaker configures the circuit breaker.
+func WithCircuitBreaker(config CircuitBreakerConfig) Option {
+	return func(s *settings) {
+		s.config.CircuitBreaker = config
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for API requests.
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *settings) {
+		s.config.HTTPClient = client
+	}
+}
+
+// WithAllowedCustomers limits tracking to the given customer IDs.
+func WithAllowedCustomers(ids ...string) Option {
+	return func(s *settings) {
+		s.config.AllowedCustomers = ids
+	}
+}
+
+// WithCustomerFilter sets the filter that decides which customers are tracked.
+func WithCustomerFilter(filter CustomerFilter) Option {
+	return func(s *settings) {
+		s.config.CustomerFilter = filter
+	}
+}
+
+// WithMeterRule sets the validation rule for events of the given meter.
+func WithMeterRule(meterToken string, rule MeterRule) Option {
+	return func(s *settings) {
+		if s.config.MeterRules == nil {
+			s.config.MeterRules = make(map[string]MeterRule)
+		}
+		s.config.MeterRules[meterToken] = rule
+	}
+}
+
+// WithSampling samples the events of the given meter.
+func WithSampling(meterToken string, sampling Sampling) Option {
+	return func(s *settings) {
+		if s.config.Sampling == nil {
+			s.config.Sampling = make(map[string]Sampling)
+		}
+		s.config.Sampling[meterToken] = sampling
+	}
+}
+
+// WithLateEvents sets how events older than the open billing period are
+// handled.
+func WithLateEvents(policy LateEventPolicy) Option {
+	return func(s *settings) {
+		s.config.LateEvents = policy
+	}
+}
+
+// WithCompression compresses request bodies of at least threshold bytes
+// with codec. A threshold of zero keeps the default of 1024 bytes.
+func WithCompression(codec Codec, threshold int) Option {
+	return func(s *settings) {
+		s.config.Compression = codec
+		s.config.CompressionThreshold = threshold
+	}
+}
+
+// WithRequeue limits how often and for how long events whose flush failed
+// transiently are put back into the queue. Zero values keep the defaults of
+// 5 attempts and 24 hours.
+func WithRequeue(maxAttempts int, maxAge time.Duration) Option {
+	return func(s *settings) {
+		s.config.MaxFlushAttempts = maxAttempts
+		s.config.MaxEventAge = maxAge
+	}
+}
+
+// WithDebug enables debug logging.
+func WithDebug() Option {
+	return func(s *settings) {
+		s.config.Debug = true
+	}
+}
+
+// New creates a new billing SDK instance configured with functional options.
+//
+// Unlike NewSDK, settings that are not given keep their documented defaults:
+// batching and retries are enabled unless WithoutBatching or WithRetry(NoRetry)
+// turn them off explicitly.
+func New(apiKey string, opts ...Option) (*SDK, error) {
+	st := settings{config: Config{APIKey: apiKey}}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&st)
+		}
+	}
+
+	if !st.batchingSet {
+		st.config.EnableBatching = true
+	}
+	if st.retry == nil {
+		policy := DefaultRetryPolicy()
+		st.retry = &policy
+	}
+
+	return newSDK(st)
+}
+
+// settingsFromConfig translates a Config into settings, keeping the
+// historical NewSDK behaviour where a false flag means "disabled".
+func settingsFromConfig(config Config) settings {
+	retry := NoRetry
+	if config.EnableRetry {
+		retry = DefaultRetryPolicy()
+		if config.MaxRetries != 0 {
+			retry.MaxAttempts = config.MaxRetries
+		}
+	}
+
+	return settings{
+		config:      config,
+		batchingSet: true,
+		retry:       &retry,
+	}
+}
@@ -0,0 +1,118 @@
+package billing
+
+import (
+	"fmt"
+	"time"
+)
+
+// Period divides time into consecutive billing periods or calendar buckets.
+type Period interface {
+	// Bounds returns the start (inclusive) and end (exclusive) of the
+	// period containing t.
+	Bounds(t time.Time) (start, end time.Time)
+}
+
+// PeriodFunc adapts an ordinary function to the Period interface.
+type PeriodFunc func(t time.Time) (start, end time.Time)
+
+// Bounds calls f(t).
+func (f PeriodFunc) Bounds(t time.Time) (start, end time.Time) {
+	return f(t)
+}
+
+var (
+	// HourlyPeriod divides time into UTC hours.
+	HourlyPeriod Period = PeriodFunc(func(t time.Time) (time.Time, time.Time) {
+		start := t.UTC().Truncate(time.Hour)
+		return start, start.Add(time.Hour)
+	})
+
+	// DailyPeriod divides time into UTC calendar days.
+	DailyPeriod Period = PeriodFunc(func(t time.Time) (time.Time, time.Time) {
+		y, m, d := t.UTC().Date()
+		start := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 0, 1)
+	})
+
+	// MonthlyPeriod divides time into UTC calendar months.
+	MonthlyPeriod Period = PeriodFunc(func(t time.Time) (time.Time, time.Time) {
+		y, m, _ := t.UTC().Date()
+		start := time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	})
+)
+
+// LateEventMode selects what happens to events older than the open billing
+// period.
+type LateEventMode int
+
+const (
+	// LateEventsAllow sends late events unchanged (default).
+	LateEventsAllow LateEventMode = iota
+
+	// LateEventsReject rejects late events with a *ValidationError.
+	LateEventsReject
+
+	// LateEventsClamp moves the timestamp of late events to the start of
+	// the open billing period.
+	LateEventsClamp
+)
+
+// LateEventPolicy controls how events timestamped before the open billing
+// period are handled. The policy is applied when an event is tracked and
+// again right before it is sent, so queued events that cross a period
+// boundary are handled too.
+type LateEventPolicy struct {
+	// Mode selects whether late events are allowed, rejected or clamped
+	Mode LateEventMode
+
+	// Period defines the billing periods (default: MonthlyPeriod)
+	Period Period
+}
+
+// applyLateEventPolicy rejects or clamps params if its timestamp lies before
+// the billing period containing now.
+func (s *SDK) applyLateEventPolicy(params *TrackEventParams, now time.Time) error {
+	policy := s.config.LateEvents
+	if policy.Mode == LateEventsAllow || params.Timestamp == nil {
+		return nil
+	}
+
+	period := policy.Period
+	if period == nil {
+		period = MonthlyPeriod
+	}
+	open, _ := period.Bounds(now)
+	if !params.Timestamp.Before(open) {
+		return nil
+	}
+
+	if policy.Mode == LateEventsClamp {
+		s.log("Clamping late event timestamp %s to %s", params.Timestamp.Format(time.RFC3339Nano), open.Format(time.RFC3339Nano))
+		params.Timestamp = &open
+		return nil
+	}
+
+	return &ValidationError{Errors: []FieldError{{
+		Field:   "timestamp",
+		Message: fmt.Sprintf("is before the open billing period starting %s", open.Format(time.RFC3339)),
+	}}}
+}
+
+// apiTimeLayouts are the timestamp formats accepted in API responses.
+// Timestamps without a zone are interpreted as UTC.
+var apiTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+}
+
+func parseAPITime(value string) time.Time {
+	for _, layout := range apiTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
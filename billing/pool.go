@@ -0,0 +1,65 @@
+package billing
+
+import (
+	"context"
+	"sync"
+)
+
+const defaultMaxConcurrentRequests = 10
+
+// workerPool runs jobs on a fixed number of goroutines. It bounds the number
+// of concurrent API requests across all flushes and immediate sends.
+type workerPool struct {
+	jobs    chan func()
+	wg      sync.WaitGroup
+	mu      sync.RWMutex
+	stopped bool
+}
+
+func newWorkerPool(size int) *workerPool {
+	p := &workerPool{jobs: make(chan func())}
+	p.wg.Add(size)
+	for i := 0; i < size; i++ {
+		go func() {
+			defer p.wg.Done()
+			for job := range p.jobs {
+				job()
+			}
+		}()
+	}
+	return p
+}
+
+// submit hands job to the next free worker. It blocks until a worker takes
+// the job or ctx is done.
+func (p *workerPool) submit(ctx context.Context, job func()) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.stopped {
+		return ErrClosed
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	select {
+	case p.jobs <- job:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// stop waits for running jobs to finish and stops all workers. Jobs
+// submitted afterwards are rejected.
+func (p *workerPool) stop() {
+	p.mu.Lock()
+	if !p.stopped {
+		p.stopped = true
+		close(p.jobs)
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
@@ -0,0 +1,185 @@
+package billing
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultMaxFlushAttempts = 5
+	defaultMaxEventAge      = 24 * time.Hour
+)
+
+// eventQueue is the batching queue. It is split into shards with their own
+// lock, and every push picks a shard at random, so concurrent Track calls
+// rarely contend with each other or with a flush draining the queue.
+// Events are not kept in strict FIFO order across shards; every event
+// carries its own timestamp.
+type eventQueue struct {
+	shards []queueShard
+	mask   uint32
+	length atomic.Int64
+	cursor atomic.Uint32 // shard where the next drain starts
+}
+
+// queuedEvent is an event waiting in the queue, with the number of flushes
+// that tried to send it, the time it was first queued and, for TrackAsync,
+// the Delivery to complete.
+type queuedEvent struct {
+	params   TrackEventParams
+	attempts int
+	queuedAt time.Time
+	delivery *Delivery
+}
+
+// flushTracker keeps the events held by running flushes, so that a
+// Shutdown whose deadline passes can report them as unsent. Once it is
+// abandoned, flushes no longer requeue or complete the events it handed
+// over.
+type flushTracker struct {
+	mu        sync.Mutex
+	events    map[*queuedEvent]struct{}
+	abandoned bool
+}
+
+// take drains up to limit events from q and holds them until finish.
+func (t *flushTracker) take(q *eventQueue, limit int) []queuedEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.abandoned {
+		return nil
+	}
+	batch := q.drain(limit)
+	if t.events == nil {
+		t.events = make(map[*queuedEvent]struct{})
+	}
+	for i := range batch {
+		t.events[&batch[i]] = struct{}{}
+	}
+	return batch
+}
+
+// finish releases e. It returns false if e was handed over by abandon, in
+// which case the caller must neither requeue nor complete it.
+func (t *flushTracker) finish(e *queuedEvent) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.events[e]; !ok {
+		return false
+	}
+	delete(t.events, e)
+	return true
+}
+
+// abandon stops tracking and returns the events still held by flushes,
+// followed by those left in q.
+func (t *flushTracker) abandon(q *eventQueue) []queuedEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.abandoned = true
+	events := make([]queuedEvent, 0, len(t.events)+q.len())
+	for e := range t.events {
+		events = append(events, *e)
+	}
+	t.events = nil
+	return append(events, q.drain(0)...)
+}
+
+type queueShard struct {
+	mu     sync.Mutex
+	events []queuedEvent
+
+	// Keep shards on separate cache lines
+	_ [64]byte
+}
+
+func newEventQueue() *eventQueue {
+	n := 1
+	for n < runtime.GOMAXPROCS(0) {
+		n <<= 1
+	}
+	return &eventQueue{
+		shards: make([]queueShard, n),
+		mask:   uint32(n - 1),
+	}
+}
+
+// push appends an event and returns the new queue length.
+func (q *eventQueue) push(event queuedEvent) int {
+	// Count the event before it is visible to drain, so that length never
+	// falls below the number of events in the shards
+	n := q.length.Add(1)
+
+	shard := &q.shards[rand.Uint32()&q.mask]
+	shard.mu.Lock()
+	shard.events = append(shard.events, event)
+	shard.mu.Unlock()
+	return int(n)
+}
+
+// len returns the number of queued events.
+func (q *eventQueue) len() int {
+	return int(q.length.Load())
+}
+
+// drain removes and returns up to limit events (all of them if limit is 0).
+func (q *eventQueue) drain(limit int) []queuedEvent {
+	n := q.len()
+	if n <= 0 {
+		return nil
+	}
+	if limit > 0 && limit < n {
+		n = limit
+	}
+
+	batch := make([]queuedEvent, 0, n)
+	start := q.cursor.Add(1)
+	for i := range q.shards {
+		shard := &q.shards[(start+uint32(i))&q.mask]
+		shard.mu.Lock()
+		take := len(shard.events)
+		if limit > 0 && take > limit-len(batch) {
+			take = limit - len(batch)
+		}
+		batch = append(batch, shard.events[:take]...)
+		remaining := copy(shard.events, shard.events[take:])
+		for j := remaining; j < len(shard.events); j++ {
+			shard.events[j] = queuedEvent{}
+		}
+		shard.events = shard.events[:remaining]
+		shard.mu.Unlock()
+
+		if limit > 0 && len(batch) == limit {
+			break
+		}
+	}
+
+	q.length.Add(-int64(len(batch)))
+	return batch
+}
+
+// isTransient reports whether a batched event that failed with err may be
+// sent successfully later and should go back into the queue.
+func isTransient(err error) bool {
+	if errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrClosed) || isCancellation(err) {
+		return true
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || isOutage(err)
+	}
+	return isOutage(err)
+}
@@ -0,0 +1,51 @@
+package billing
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// The queue is unexported, so this regression test lives in the package
+func TestEventQueueConcurrentPushAndDrain(t *testing.T) {
+	// One CPU hands the shard lock straight to a waiting drain, which is
+	// where a push used to be counted after the drain had taken it
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+
+	q := newEventQueue()
+	const pushers, events = 8, 20000
+
+	var wg sync.WaitGroup
+	for i := 0; i < pushers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < events; j++ {
+				q.push(queuedEvent{})
+			}
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	drained := 0
+	for draining := true; draining; {
+		select {
+		case <-done:
+			draining = false
+		default:
+		}
+		drained += len(q.drain(0))
+		if n := q.len(); n < 0 {
+			t.Fatalf("Expected a non-negative queue length, got %d", n)
+		}
+	}
+	drained += len(q.drain(0))
+
+	if drained != pushers*events || q.len() != 0 {
+		t.Errorf("Expected %d events drained and none left, got %d drained, length %d", pushers*events, drained, q.len())
+	}
+}
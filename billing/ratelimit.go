@@ -0,0 +1,158 @@
+package billing
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit limits the rate of outbound API requests with a token bucket.
+type RateLimit struct {
+	// RequestsPerSecond is the sustained request rate (0 = unlimited)
+	RequestsPerSecond float64
+
+	// Burst is the number of requests that may be sent at once
+	// (default: RequestsPerSecond rounded up, at least 1)
+	Burst int
+}
+
+// rateLimiter is a token bucket in front of every outbound request. Besides
+// the configured rate it honours pauses requested by the API through 429
+// responses and rate limit headers, even when no rate is configured.
+type rateLimiter struct {
+	mu          sync.Mutex
+	rate        float64 // tokens per second, 0 = unlimited
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	burst := float64(limit.Burst)
+	if burst <= 0 {
+		burst = limit.RequestsPerSecond
+		if burst < 1 {
+			burst = 1
+		}
+	}
+	return &rateLimiter{
+		rate:   limit.RequestsPerSecond,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until a request may be sent or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	for {
+		delay := l.reserve(time.Now())
+		if delay <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// reserve takes a token if one is available and returns 0, or returns how
+// long to wait before trying again.
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+	if l.rate <= 0 {
+		return 0
+	}
+
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// pause stops all requests until the given time.
+func (l *rateLimiter) pause(until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+	l.tokens = 0
+}
+
+func (l *rateLimiter) blockedUntil() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.pausedUntil
+}
+
+// observe adapts the limiter to a response: a 429 pauses requests for the
+// Retry-After period, and an exhausted X-RateLimit-Remaining pauses them
+// until X-RateLimit-Reset.
+func (l *rateLimiter) observe(statusCode int, header http.Header, now time.Time) {
+	if statusCode == http.StatusTooManyRequests {
+		delay := retryAfter(header, now)
+		if delay <= 0 {
+			delay = time.Second
+		}
+		l.pause(now.Add(delay))
+		return
+	}
+
+	if header.Get("X-RateLimit-Remaining") == "0" {
+		if reset := rateLimitReset(header, now); reset.After(now) {
+			l.pause(reset)
+		}
+	}
+}
+
+// retryAfter parses the Retry-After header, given in seconds or as an HTTP
+// date.
+func retryAfter(header http.Header, now time.Time) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// rateLimitReset parses X-RateLimit-Reset, given either as a Unix timestamp
+// or as a number of seconds from now.
+func rateLimitReset(header http.Header, now time.Time) time.Time {
+	value, err := strconv.ParseFloat(header.Get("X-RateLimit-Reset"), 64)
+	if err != nil || value <= 0 {
+		return time.Time{}
+	}
+	// Values this large can only be Unix timestamps
+	if value > 1e9 {
+		return time.Unix(0, int64(value*float64(time.Second)))
+	}
+	return now.Add(time.Duration(value * float64(time.Second)))
+}
@@ -0,0 +1,205 @@
+package billing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// APIError is returned when the API responds with an error status code.
+type APIError struct {
+	StatusCode int
+	Status     string
+	Message    string
+	Header     http.Header
+
+	action string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("Failed to %s: %d %s - %s", e.action, e.StatusCode, e.Status, e.Message)
+}
+
+// apiRequest describes a single call to the Fluxrate API.
+type apiRequest struct {
+	method string
+	path   string      // appended to Config.APIUrl
+	url    string      // absolute URL, used instead of path if set
+	body   interface{} // encoded as JSON if not nil
+	action string      // used in error messages, e.g. "track event"
+
+	// probe requests check the API's health; they bypass the circuit
+	// breaker and do not affect it
+	probe bool
+}
+
+// do sends req and decodes the JSON response into out (if not nil). Error
+// statuses are returned as *APIError.
+func (s *SDK) do(ctx context.Context, req apiRequest, out interface{}) error {
+	var payload []byte
+	if req.body != nil {
+		var err error
+		payload, err = json.Marshal(req.body)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal request body: %w", err)
+		}
+	}
+
+	if !req.probe && !s.breaker.allow() {
+		return ErrCircuitOpen
+	}
+
+	compress := s.shouldCompress(payload)
+	respBody, err := s.send(ctx, req, payload, compress)
+
+	var apiErr *APIError
+	if compress && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnsupportedMediaType {
+		// The server does not accept compressed bodies; stop compressing
+		s.compressionDisabled.Store(true)
+		s.log("Server rejected %s request body, disabling compression", s.config.Compression.Encoding())
+		respBody, err = s.send(ctx, req, payload, false)
+	}
+
+	switch {
+	case req.probe:
+	case err != nil && ctx.Err() != nil:
+		// Cancelled by the caller; this says nothing about the API
+		s.breaker.abandon()
+	default:
+		s.breaker.record(err)
+	}
+	if err != nil {
+		s.stats.recordError(err)
+		return err
+	}
+
+	if out != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("Failed to parse response: %w", err)
+		}
+	}
+	return nil
+}
+
+// doWithRetry calls do, retrying transient failures with the SDK's retry
+// policy. Permanent rejections and ErrCircuitOpen are returned right away.
+func (s *SDK) doWithRetry(ctx context.Context, req apiRequest, out interface{}) error {
+	var lastErr error
+	maxAttempts := s.retry.MaxAttempts
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		err := s.do(ctx, req, out)
+		if err == nil {
+			return nil
+		}
+
+		lastErr = err
+		s.log("Attempt %d/%d to %s failed: %v", attempt, maxAttempts, req.action, err)
+
+		// Permanent rejections fail the same way on every attempt
+		if errors.Is(err, ErrCircuitOpen) || !isTransient(err) {
+			return err
+		}
+
+		if attempt < maxAttempts {
+			s.stats.retried.Add(1)
+
+			// Exponential backoff
+			delay := s.retry.backoff(attempt)
+			s.log("Retrying in %v...", delay)
+
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	return lastErr
+}
+
+func (s *SDK) send(ctx context.Context, req apiRequest, payload []byte, compress bool) ([]byte, error) {
+	var body io.Reader
+	encoding := ""
+	if payload != nil {
+		if compress {
+			compressed, err := s.config.Compression.Encode(payload)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to compress request body: %w", err)
+			}
+			payload = compressed
+			encoding = s.config.Compression.Encoding()
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	url := req.url
+	if url == "" {
+		url = s.config.APIUrl + req.path
+	}
+
+	// Marks the request so that a MeteringTransport never meters it
+	ctx = context.WithValue(ctx, sdkRequestKey, true)
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create request: %w", err)
+	}
+
+	if payload != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if encoding != "" {
+		httpReq.Header.Set("Content-Encoding", encoding)
+	}
+	httpReq.Header.Set("X-API-Key", s.config.APIKey)
+
+	if err := s.limiter.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	s.stats.inFlight.Add(1)
+	resp, err := s.httpClient.Do(httpReq)
+	s.stats.inFlight.Add(-1)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	s.limiter.observe(resp.StatusCode, resp.Header, time.Now())
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		var errResp struct {
+			Detail  string `json:"detail"`
+			Message string `json:"message"`
+		}
+		json.Unmarshal(respBody, &errResp)
+		errMsg := errResp.Detail
+		if errMsg == "" {
+			errMsg = errResp.Message
+		}
+		if errMsg == "" {
+			errMsg = "Unknown error"
+		}
+		return nil, &APIError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Message:    errMsg,
+			Header:     resp.Header,
+			action:     req.action,
+		}
+	}
+
+	return respBody, nil
+}
@@ -0,0 +1,73 @@
+package billing
+
+import (
+	"hash/fnv"
+	"math"
+	"math/rand"
+)
+
+// SampleRateKey is the metadata key that records the sample rate of a
+// sampled event.
+const SampleRateKey = "sample_rate"
+
+// Sampling keeps a fraction of a meter's events and scales the quantity of
+// each kept event by 1/Rate, so that totals stay unbiased. It trades
+// exactness for cost on high-volume meters and is applied after the
+// customer filter.
+type Sampling struct {
+	// Rate is the fraction of events kept, between 0 and 1 (1 = all events)
+	Rate float64
+
+	// Deterministic decides by a hash of the customer and IdempotencyKey
+	// instead of at random, so that every retry of an event gets the same
+	// decision. Events without an IdempotencyKey are sampled at random.
+	Deterministic bool
+}
+
+// keep reports whether an event for customer with the given idempotency
+// key is kept.
+func (p Sampling) keep(customer, key string) bool {
+	if p.Deterministic && key != "" {
+		h := fnv.New64a()
+		h.Write([]byte(customer))
+		h.Write([]byte{0})
+		h.Write([]byte(key))
+		return float64(h.Sum64()>>11)/(1<<53) < p.Rate
+	}
+	return rand.Float64() < p.Rate
+}
+
+// sample applies the sampling configured for the meter of params. It
+// returns false if the event is dropped; kept events have the rate recorded
+// in their metadata and, unless they are readings sent by Meter.Set, their
+// quantity scaled.
+func (s *SDK) sample(params *TrackEventParams) bool {
+	p, ok := s.config.Sampling[params.MeterToken]
+	if !ok || p.Rate >= 1 || math.IsNaN(p.Rate) {
+		return true
+	}
+	if p.Rate <= 0 || !p.keep(params.CustomerExternalID, params.IdempotencyKey) {
+		return false
+	}
+
+	reading, _ := params.Metadata[ReadingKey].(bool)
+	switch {
+	case reading:
+		// A reading is a value, not an amount, so it is kept as is
+	case params.QuantityDecimal != nil:
+		// The scaled quantity is an estimate, so an inexact factor is fine
+		factor, _ := DecimalFromFloat(1 / p.Rate)
+		scaled := params.QuantityDecimal.Mul(factor)
+		params.QuantityDecimal = &scaled
+	default:
+		params.Quantity /= p.Rate
+	}
+
+	metadata := make(map[string]interface{}, len(params.Metadata)+1)
+	for k, v := range params.Metadata {
+		metadata[k] = v
+	}
+	metadata[SampleRateKey] = p.Rate
+	params.Metadata = metadata
+	return true
+}
@@ -4,15 +4,16 @@
 package billing
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -27,25 +28,80 @@ type Config struct {
 	// APIUrl is the API base URL (default: https://api.fluxrate.co/api/v1)
 	APIUrl string `json:"api_url"`
 
-	// EnableBatching enables automatic batching of events (default: true)
+	// EnableBatching enables automatic batching of events.
+	// NewSDK treats false as disabled; use New with WithoutBatching to
+	// disable batching while keeping the other defaults.
 	EnableBatching bool `json:"enable_batching"`
 
-	// BatchSize is the batch size for automatic batching (default: 10)
+	// BatchSize is the batch size for automatic batching (default: 100)
 	BatchSize int `json:"batch_size"`
 
 	// BatchInterval is the batch interval (default: 5 seconds)
 	BatchInterval time.Duration `json:"batch_interval"`
 
-	// EnableRetry enables automatic retry on failure (default: true)
+	// EnableRetry enables automatic retry on failure.
+	// NewSDK treats false as disabled; use New with WithRetry for an
+	// explicit RetryPolicy.
 	EnableRetry bool `json:"enable_retry"`
 
-	// MaxRetries is the maximum retry attempts (default: 3)
+	// MaxRetries is the maximum number of attempts when EnableRetry is set
+	// (default: 10). Zero selects the default; use New with WithRetry(NoRetry)
+	// to send each request only once.
 	MaxRetries int `json:"max_retries"`
 
 	// AllowedCustomers is a list of customer IDs to allow requests for.
 	// If empty, all customers are allowed.
 	AllowedCustomers []string `json:"allowed_customers"`
 
+	// CustomerFilter decides which customers are tracked (optional).
+	// If AllowedCustomers is also set, a customer must pass both.
+	// Use SDK.SetCustomerFilter to replace the filter at runtime.
+	CustomerFilter CustomerFilter `json:"-"`
+
+	// MaxConcurrentRequests is the number of API requests that may be in
+	// flight at once, shared by all flushes and TrackImmediate (default: 10)
+	MaxConcurrentRequests int `json:"max_concurrent_requests"`
+
+	// RateLimit limits the rate of outbound API requests (default: unlimited).
+	// Pauses requested by the API through 429 responses and rate limit
+	// headers are honoured either way.
+	RateLimit RateLimit `json:"rate_limit"`
+
+	// CircuitBreaker configures the circuit breaker that stops requests
+	// while the API is unavailable (default: enabled, see CircuitBreakerConfig)
+	CircuitBreaker CircuitBreakerConfig `json:"-"`
+
+	// MeterRules holds per-meter validation rules, keyed by meter token (optional)
+	MeterRules map[string]MeterRule `json:"meter_rules"`
+
+	// MaxFutureSkew is how far in the future an event timestamp may be
+	// before Track rejects it (default: 5 minutes)
+	MaxFutureSkew time.Duration `json:"max_future_skew"`
+
+	// LateEvents controls events timestamped before the open billing period
+	// (default: allowed unchanged)
+	LateEvents LateEventPolicy `json:"-"`
+
+	// Compression compresses request bodies, e.g. GzipCodec{} (optional).
+	// Compression is turned off automatically if the server answers 415.
+	Compression Codec `json:"-"`
+
+	// CompressionThreshold is the smallest body size in bytes that is
+	// compressed (default: 1024)
+	CompressionThreshold int `json:"compression_threshold"`
+
+	// MaxFlushAttempts is the number of flushes that may try to send a
+	// batched event before it is dropped (default: 5). Events whose flush
+	// failed transiently are put back into the queue until then.
+	MaxFlushAttempts int `json:"max_flush_attempts"`
+
+	// MaxEventAge is how long a batched event may stay queued after failed
+	// flushes before it is dropped (default: 24 hours)
+	MaxEventAge time.Duration `json:"max_event_age"`
+
+	// Sampling holds per-meter sampling, keyed by meter token (optional)
+	Sampling map[string]Sampling `json:"sampling"`
+
 	// Debug enables debug logging (default: false)
 	Debug bool `json:"debug"`
 
@@ -64,10 +120,16 @@ type TrackEventParams struct {
 	// Quantity is the usage quantity to track
 	Quantity float64 `json:"quantity"`
 
-	// Timestamp is an optional timestamp (default: now)
+	// QuantityDecimal is an exact alternative to Quantity (optional).
+	// If set, it takes precedence over Quantity and is sent without loss.
+	QuantityDecimal *Decimal `json:"-"`
+
+	// Timestamp is an optional timestamp (default: the time Track is called).
+	// It is sent in UTC with full sub-second precision.
 	Timestamp *time.Time `json:"timestamp,omitempty"`
 
-	// IdempotencyKey is an optional key to prevent duplicates
+	// IdempotencyKey is an optional key to prevent duplicates. Batched
+	// events without one get a generated key when they are queued.
 	IdempotencyKey string `json:"idempotency_key,omitempty"`
 
 	// Metadata is optional additional data
@@ -83,13 +145,47 @@ type TrackEventResponse struct {
 	Timestamp  string                 `json:"timestamp"`
 	CreatedAt  string                 `json:"created_at"`
 	MetaData   map[string]interface{} `json:"meta_data,omitempty"`
+
+	// EventTime and CreatedTime hold Timestamp and CreatedAt parsed as UTC
+	// times. They are zero if the API returned a value that is not a timestamp.
+	EventTime   time.Time `json:"-"`
+	CreatedTime time.Time `json:"-"`
+}
+
+// UnmarshalJSON decodes the response and parses its time fields.
+func (r *TrackEventResponse) UnmarshalJSON(data []byte) error {
+	type rawResponse TrackEventResponse
+	if err := json.Unmarshal(data, (*rawResponse)(r)); err != nil {
+		return err
+	}
+	r.EventTime = parseAPITime(r.Timestamp)
+	r.CreatedTime = parseAPITime(r.CreatedAt)
+	return nil
+}
+
+// QuantityDecimal parses the quantity returned by the API as an exact decimal.
+func (r *TrackEventResponse) QuantityDecimal() (Decimal, error) {
+	return ParseDecimal(r.Quantity)
 }
 
 // BatchResult contains the results of a batch flush.
 type BatchResult struct {
 	Successful int
-	Failed     int
-	Errors     []BatchError
+
+	// Failed is the number of events the API rejected permanently
+	Failed int
+
+	// Errors lists the failed and dropped events
+	Errors []BatchError
+
+	// Requeued is the number of events that were put back into the queue
+	// for the next flush: events that failed transiently, were cancelled or
+	// were not attempted because the circuit breaker is open.
+	Requeued int
+
+	// Dropped is the number of events that failed transiently but had used
+	// up MaxFlushAttempts or were older than MaxEventAge
+	Dropped int
 }
 
 // BatchError represents an error that occurred while sending an event.
@@ -100,17 +196,47 @@ type BatchError struct {
 
 // SDK is the main billing SDK client.
 type SDK struct {
-	config           Config
-	httpClient       *http.Client
-	batchQueue       []TrackEventParams
-	batchMu          sync.Mutex
-	stopChan         chan struct{}
-	wg               sync.WaitGroup
-	allowedCustomers map[string]bool
+	// Events looks up tracked events
+	Events *EventsClient
+
+	config         Config
+	apiHost        string
+	retry          RetryPolicy
+	httpClient     *http.Client
+	queue          *eventQueue
+	flushMu        sync.Mutex
+	flushSignal    chan struct{}
+	pool           *workerPool
+	breaker        *circuitBreaker
+	limiter        *rateLimiter
+	stats          sdkStats
+	stopChan       chan struct{}
+	wg             sync.WaitGroup
+	lifecycleMu    sync.RWMutex
+	closed         bool
+	ops            sync.WaitGroup
+	shutdownOnce   sync.Once
+	shutdownDone   chan struct{}
+	flushing       flushTracker
+	async          asyncQueue     // unbatched TrackAsync events
+	allowList      CustomerFilter // Config.AllowedCustomers, nil if unset
+	customerFilter atomic.Pointer[filterHolder]
+
+	compressionDisabled atomic.Bool
 }
 
 // NewSDK creates a new billing SDK instance.
+//
+// Zero values in config select the defaults, except for EnableBatching and
+// EnableRetry, which are disabled when false. Use New to configure the SDK
+// with functional options instead.
 func NewSDK(config Config) (*SDK, error) {
+	return newSDK(settingsFromConfig(config))
+}
+
+func newSDK(st settings) (*SDK, error) {
+	config := st.config
+
 	// Validate API key
 	if config.APIKey == "" || !strings.HasPrefix(config.APIKey, "sk_") {
 		return nil, fmt.Errorf("Invalid API key: must start with 'sk_live_' or 'sk_test_'")
@@ -118,21 +244,38 @@ func NewSDK(config Config) (*SDK, error) {
 
 	// Set defaults
 	if config.APIUrl == "" {
-		config.APIUrl = "https://api.fluxrate.co/api/v1"
+		config.APIUrl = defaultAPIUrl
 	}
-	if config.BatchSize == 0 {
-		config.BatchSize = 100
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultBatchSize
 	}
-	if config.BatchInterval == 0 {
-		config.BatchInterval = 5 * time.Second
+	if config.BatchInterval <= 0 {
+		config.BatchInterval = defaultBatchInterval
 	}
-	if config.MaxRetries == 0 {
-		config.MaxRetries = 10
+	if config.MaxConcurrentRequests <= 0 {
+		config.MaxConcurrentRequests = defaultMaxConcurrentRequests
 	}
+	if config.CompressionThreshold <= 0 {
+		config.CompressionThreshold = defaultCompressionThreshold
+	}
+	if config.MaxFutureSkew <= 0 {
+		config.MaxFutureSkew = defaultMaxFutureSkew
+	}
+	if config.MaxFlushAttempts <= 0 {
+		config.MaxFlushAttempts = defaultMaxFlushAttempts
+	}
+	if config.MaxEventAge <= 0 {
+		config.MaxEventAge = defaultMaxEventAge
+	}
+
+	retry := st.retry.normalize()
+	config.EnableRetry = retry.MaxAttempts > 1
+	config.MaxRetries = retry.MaxAttempts
 
-	allowedCustomers := make(map[string]bool)
-	for _, id := range config.AllowedCustomers {
-		allowedCustomers[id] = true
+	// AllowedCustomers is a fixed stage that SetCustomerFilter never replaces
+	var allowList CustomerFilter
+	if len(config.AllowedCustomers) > 0 {
+		allowList = AllowList(config.AllowedCustomers...)
 	}
 
 	httpClient := config.HTTPClient
@@ -142,16 +285,31 @@ func NewSDK(config Config) (*SDK, error) {
 		}
 	}
 
+	// Used by MeteringTransport to recognize calls to the API
+	var apiHost string
+	if u, err := url.Parse(config.APIUrl); err == nil {
+		apiHost = u.Host
+	}
+
 	sdk := &SDK{
-		config:           config,
-		httpClient:       httpClient,
-		batchQueue:       make([]TrackEventParams, 0),
-		stopChan:         make(chan struct{}),
-		allowedCustomers: allowedCustomers,
+		config:       config,
+		apiHost:      apiHost,
+		retry:        retry,
+		httpClient:   httpClient,
+		queue:        newEventQueue(),
+		flushSignal:  make(chan struct{}, 1),
+		pool:         newWorkerPool(config.MaxConcurrentRequests),
+		breaker:      newCircuitBreaker(config.CircuitBreaker),
+		limiter:      newRateLimiter(config.RateLimit),
+		stopChan:     make(chan struct{}),
+		shutdownDone: make(chan struct{}),
+		allowList:    allowList,
 	}
+	sdk.Events = &EventsClient{sdk: sdk}
+	sdk.SetCustomerFilter(config.CustomerFilter)
 
-	sdk.log("SDK initialized: version=%s, apiUrl=%s, batching=%v, batchSize=%d",
-		Version, config.APIUrl, config.EnableBatching, config.BatchSize)
+	sdk.log("SDK initialized: version=%s, apiUrl=%s, batching=%v, batchSize=%d, maxAttempts=%d",
+		Version, config.APIUrl, config.EnableBatching, config.BatchSize, retry.MaxAttempts)
 
 	// Start batch processing if enabled
 	if config.EnableBatching {
@@ -163,81 +321,137 @@ func NewSDK(config Config) (*SDK, error) {
 
 // Track tracks a single usage event.
 // If batching is enabled, the event will be queued and sent in a batch.
+// Invalid events are rejected with a *ValidationError before being queued.
+// After Shutdown, Track returns ErrClosed.
 func (s *SDK) Track(ctx context.Context, params TrackEventParams) (*TrackEventResponse, error) {
-	// Check allowed customers
-	if len(s.allowedCustomers) > 0 && !s.allowedCustomers[params.CustomerExternalID] {
-		s.log("Skipping event for disallowed customer: %s", params.CustomerExternalID)
+	return s.track(ctx, params, nil)
+}
+
+// track implements Track, checking rule in addition to the configured
+// validation rules (rule may be nil).
+func (s *SDK) track(ctx context.Context, params TrackEventParams, rule *MeterRule) (*TrackEventResponse, error) {
+	if !s.begin() {
+		return nil, ErrClosed
+	}
+	defer s.ops.Done()
+
+	params, err := s.prepare(params, time.Now(), rule)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check allowed customers and sampling
+	if !s.admit(&params) {
 		return nil, nil
 	}
+	s.stats.tracked.Add(1)
 
 	if s.config.EnableBatching {
-		s.batchMu.Lock()
-		s.batchQueue = append(s.batchQueue, params)
-		queueLen := len(s.batchQueue)
-		s.batchMu.Unlock()
-
-		s.log("Event queued for batching (%d/%d)", queueLen, s.config.BatchSize)
-
-		// Flush if batch is full
-		if queueLen >= s.config.BatchSize {
-			_, err := s.flushBatch(ctx)
-			if err != nil {
-				s.log("Batch flush error: %v", err)
-			}
+		// A requeued event may be sent again after a failure the API
+		// already committed; the key keeps it from being counted twice
+		if params.IdempotencyKey == "" {
+			params.IdempotencyKey = newEventID()
 		}
+		s.enqueue(queuedEvent{params: params, queuedAt: time.Now()})
 
 		// Return nil since event is queued and will be sent in batch
 		// Events are sent when batch is full, interval expires, or Flush() is called
+		// Use TrackAsync to wait for the delivery
 		return nil, nil
 	}
 
-	return s.TrackImmediate(ctx, params)
+	return s.sendImmediate(ctx, params)
 }
 
 // TrackImmediate tracks an event immediately without batching.
 func (s *SDK) TrackImmediate(ctx context.Context, params TrackEventParams) (*TrackEventResponse, error) {
-	return s.sendEventWithRetry(ctx, params)
+	if !s.begin() {
+		return nil, ErrClosed
+	}
+	defer s.ops.Done()
+
+	params, err := s.prepare(params, time.Now(), nil)
+	if err != nil {
+		return nil, err
+	}
+	s.stats.tracked.Add(1)
+
+	return s.sendImmediate(ctx, params)
 }
 
 // Flush manually flushes the current batch.
+// If another flush is running, Flush waits for it to finish first.
 func (s *SDK) Flush(ctx context.Context) (*BatchResult, error) {
-	return s.flushBatch(ctx)
-}
-
-// Shutdown gracefully shuts down the SDK (flushes pending events).
-func (s *SDK) Shutdown(ctx context.Context) error {
-	s.log("Shutting down SDK...")
+	if !s.begin() {
+		return nil, ErrClosed
+	}
+	defer s.ops.Done()
 
-	// Signal stop to batch timer
-	close(s.stopChan)
+	return s.flushBatch(ctx, 0)
+}
 
-	// Wait for batch timer to stop
-	s.wg.Wait()
+// Private methods
 
-	// Flush remaining events
-	s.batchMu.Lock()
-	queueLen := len(s.batchQueue)
-	s.batchMu.Unlock()
+// prepare validates params against the configured rules and rule (may be
+// nil), stamps and normalizes its timestamp to UTC and applies the late
+// event policy.
+func (s *SDK) prepare(params TrackEventParams, now time.Time, rule *MeterRule) (TrackEventParams, error) {
+	if err := s.validate(params, now, rule); err != nil {
+		return params, err
+	}
 
-	if queueLen > 0 {
-		_, err := s.flushBatch(ctx)
-		if err != nil {
-			return fmt.Errorf("Failed to flush remaining events: %w", err)
-		}
+	ts := now.UTC()
+	if params.Timestamp != nil {
+		ts = params.Timestamp.UTC()
 	}
+	params.Timestamp = &ts
 
-	s.log("SDK shutdown complete")
-	return nil
+	if err := s.applyLateEventPolicy(&params, now); err != nil {
+		return params, err
+	}
+	return params, nil
 }
 
-// Private methods
-
 func (s *SDK) log(format string, args ...interface{}) {
 	if s.config.Debug {
 		log.Printf("[BillingSDK] "+format, args...)
 	}
 }
 
+// admit applies the customer filter and then the meter's sampling to
+// params. It returns false if the event is skipped.
+func (s *SDK) admit(params *TrackEventParams) bool {
+	if !s.customerAllowed(params.CustomerExternalID) {
+		s.log("Skipping event for disallowed customer: %s", params.CustomerExternalID)
+		s.stats.filtered.Add(1)
+		return false
+	}
+	if !s.sample(params) {
+		s.stats.sampledOut.Add(1)
+		return false
+	}
+	return true
+}
+
+// enqueue adds an event to the batching queue and signals the batch
+// goroutine once a full batch is queued.
+func (s *SDK) enqueue(e queuedEvent) {
+	queueLen := s.queue.push(e)
+
+	s.log("Event queued for batching (%d/%d)", queueLen, s.config.BatchSize)
+
+	// Flush in the background if batch is full
+	if queueLen >= s.config.BatchSize {
+		select {
+		case s.flushSignal <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// startBatchTimer starts the goroutine that flushes the queue. It is the
+// only place where background flushes run, so the interval flush and a flush
+// triggered by a full batch never overlap.
 func (s *SDK) startBatchTimer() {
 	s.wg.Add(1)
 	go func() {
@@ -248,16 +462,9 @@ func (s *SDK) startBatchTimer() {
 		for {
 			select {
 			case <-ticker.C:
-				s.batchMu.Lock()
-				queueLen := len(s.batchQueue)
-				s.batchMu.Unlock()
-
-				if queueLen > 0 {
-					_, err := s.flushBatch(context.Background())
-					if err != nil {
-						s.log("Batch flush error: %v", err)
-					}
-				}
+				s.backgroundFlush(1)
+			case <-s.flushSignal:
+				s.backgroundFlush(s.config.BatchSize)
 			case <-s.stopChan:
 				return
 			}
@@ -265,17 +472,46 @@ func (s *SDK) startBatchTimer() {
 	}()
 }
 
-func (s *SDK) flushBatch(ctx context.Context) (*BatchResult, error) {
-	s.batchMu.Lock()
-	if len(s.batchQueue) == 0 {
-		s.batchMu.Unlock()
-		return &BatchResult{Successful: 0, Failed: 0, Errors: nil}, nil
+func (s *SDK) stopping() bool {
+	select {
+	case <-s.stopChan:
+		return true
+	default:
+		return false
+	}
+}
+
+// backgroundFlush sends batches of up to BatchSize events while at least
+// threshold events are queued. It stops early when the SDK shuts down or
+// the circuit breaker is open; the next tick tries again.
+func (s *SDK) backgroundFlush(threshold int) {
+	for s.queue.len() >= threshold && !s.stopping() {
+		if s.breaker.current() == CircuitOpen {
+			s.log("Circuit breaker is open, keeping %d events queued", s.queue.len())
+			return
+		}
+
+		result, err := s.flushBatch(context.Background(), s.config.BatchSize)
+		if err != nil {
+			s.log("Batch flush error: %v", err)
+			return
+		}
+		if result.Requeued > 0 {
+			return
+		}
 	}
+}
 
-	batch := make([]TrackEventParams, len(s.batchQueue))
-	copy(batch, s.batchQueue)
-	s.batchQueue = s.batchQueue[:0]
-	s.batchMu.Unlock()
+// flushBatch sends up to limit queued events (all of them if limit is 0)
+// through the worker pool. Flushes are serialized by flushMu.
+func (s *SDK) flushBatch(ctx context.Context, limit int) (*BatchResult, error) {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	batch := s.flushing.take(s.queue, limit)
+	if len(batch) == 0 {
+		return &BatchResult{Successful: 0, Failed: 0, Errors: nil}, nil
+	}
 
 	s.log("Flushing batch of %d events", len(batch))
 
@@ -288,64 +524,112 @@ func (s *SDK) flushBatch(ctx context.Context) (*BatchResult, error) {
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 
-	for _, event := range batch {
+	record := func(e *queuedEvent, resp *TrackEventResponse, err error, attempted bool) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// Shutdown gave up waiting and already reported the event unsent
+		if !s.flushing.finish(e) {
+			return
+		}
+		if attempted && !errors.Is(err, ErrCircuitOpen) {
+			e.attempts++
+		}
+
+		switch {
+		case err == nil:
+			result.Successful++
+			s.stats.sent.Add(1)
+		case !isTransient(err):
+			result.Failed++
+			result.Errors = append(result.Errors, BatchError{Event: e.params, Error: err})
+			s.stats.failed.Add(1)
+		case e.attempts >= s.config.MaxFlushAttempts || time.Since(e.queuedAt) > s.config.MaxEventAge:
+			result.Dropped++
+			result.Errors = append(result.Errors, BatchError{Event: e.params, Error: err})
+			s.stats.dropped.Add(1)
+		default:
+			s.queue.push(*e)
+			result.Requeued++
+			s.stats.requeued.Add(1)
+			return
+		}
+
+		if e.delivery != nil {
+			e.delivery.complete(resp, err)
+		}
+	}
+
+	for i := range batch {
+		e := &batch[i]
 		wg.Add(1)
-		go func(e TrackEventParams) {
+		err := s.pool.submit(ctx, func() {
 			defer wg.Done()
-			_, err := s.sendEventWithRetry(ctx, e)
-			mu.Lock()
-			if err != nil {
-				result.Failed++
-				result.Errors = append(result.Errors, BatchError{Event: e, Error: err})
-			} else {
-				result.Successful++
-			}
-			mu.Unlock()
-		}(event)
+			resp, err := s.sendEventWithRetry(ctx, e.params)
+			record(e, resp, err, true)
+		})
+		if err != nil {
+			// The event was never handed to a worker
+			wg.Done()
+			record(e, nil, err, false)
+		}
 	}
 
 	wg.Wait()
+	s.stats.recordFlush(result)
 
-	s.log("Batch complete: %d successful, %d failed", result.Successful, result.Failed)
+	s.log("Batch complete: %d successful, %d failed, %d requeued, %d dropped",
+		result.Successful, result.Failed, result.Requeued, result.Dropped)
 
 	return result, nil
 }
 
-func (s *SDK) sendEventWithRetry(ctx context.Context, params TrackEventParams) (*TrackEventResponse, error) {
-	var lastErr error
-	maxAttempts := 1
-	if s.config.EnableRetry {
-		maxAttempts = s.config.MaxRetries
+// sendImmediate sends a single event through the worker pool and waits for
+// the result.
+func (s *SDK) sendImmediate(ctx context.Context, params TrackEventParams) (*TrackEventResponse, error) {
+	type outcome struct {
+		resp *TrackEventResponse
+		err  error
 	}
-
-	for attempt := 1; attempt <= maxAttempts; attempt++ {
-		resp, err := s.sendEvent(ctx, params)
-		if err == nil {
-			return resp, nil
+	done := make(chan outcome, 1)
+
+	err := s.pool.submit(ctx, func() {
+		resp, err := s.sendEventWithRetry(ctx, params)
+		switch {
+		case err == nil:
+			s.stats.sent.Add(1)
+		case !errors.Is(err, ErrCircuitOpen):
+			s.stats.failed.Add(1)
 		}
+		done <- outcome{resp: resp, err: err}
+	})
+	if err != nil {
+		s.stats.failed.Add(1)
+		return nil, err
+	}
 
-		lastErr = err
-		s.log("Attempt %d/%d failed: %v", attempt, maxAttempts, err)
-
-		if attempt < maxAttempts {
-			// Exponential backoff
-			delay := time.Duration(min(1000*pow(2, attempt-1), 10000)) * time.Millisecond
-			s.log("Retrying in %v...", delay)
+	o := <-done
+	return o.resp, o.err
+}
 
-			select {
-			case <-time.After(delay):
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			}
-		}
+func (s *SDK) sendEventWithRetry(ctx context.Context, params TrackEventParams) (*TrackEventResponse, error) {
+	// Queued events may have crossed into a new billing period
+	if err := s.applyLateEventPolicy(&params, time.Now()); err != nil {
+		return nil, err
 	}
 
-	return nil, lastErr
-}
+	req := trackRequest(params)
+	s.log("Sending event: %+v", req.body)
 
-func (s *SDK) sendEvent(ctx context.Context, params TrackEventParams) (*TrackEventResponse, error) {
-	url := s.config.APIUrl + "/sdk/track"
+	var result TrackEventResponse
+	if err := s.doWithRetry(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
 
+// trackRequest builds the API request that tracks params.
+func trackRequest(params TrackEventParams) apiRequest {
 	// Build request body
 	body := map[string]interface{}{
 		"meter_token":          params.MeterToken,
@@ -353,8 +637,12 @@ func (s *SDK) sendEvent(ctx context.Context, params TrackEventParams) (*TrackEve
 		"quantity":             params.Quantity,
 	}
 
+	if params.QuantityDecimal != nil {
+		body["quantity"] = json.Number(params.QuantityDecimal.String())
+	}
+
 	if params.Timestamp != nil {
-		body["timestamp"] = params.Timestamp.Format(time.RFC3339)
+		body["timestamp"] = params.Timestamp.UTC().Format(time.RFC3339Nano)
 	}
 	if params.IdempotencyKey != "" {
 		body["idempotency_key"] = params.IdempotencyKey
@@ -363,69 +651,10 @@ func (s *SDK) sendEvent(ctx context.Context, params TrackEventParams) (*TrackEve
 		body["metadata"] = params.Metadata
 	}
 
-	s.log("Sending event: %+v", body)
-
-	jsonBody, err := json.Marshal(body)
-	if err != nil {
-		return nil, fmt.Errorf("Failed to marshal request body: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return nil, fmt.Errorf("Failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-API-Key", s.config.APIKey)
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("Failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("Failed to read response body: %w", err)
-	}
-
-	if resp.StatusCode >= 400 {
-		var errResp struct {
-			Detail  string `json:"detail"`
-			Message string `json:"message"`
-		}
-		json.Unmarshal(respBody, &errResp)
-		errMsg := errResp.Detail
-		if errMsg == "" {
-			errMsg = errResp.Message
-		}
-		if errMsg == "" {
-			errMsg = "Unknown error"
-		}
-		return nil, fmt.Errorf("Failed to track event: %d %s - %s", resp.StatusCode, resp.Status, errMsg)
-	}
-
-	var result TrackEventResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("Failed to parse response: %w", err)
-	}
-
-	return &result, nil
-}
-
-// Helper functions
-
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
-
-func pow(base, exp int) int {
-	result := 1
-	for i := 0; i < exp; i++ {
-		result *= base
+	return apiRequest{
+		method: "POST",
+		path:   "/sdk/track",
+		body:   body,
+		action: "track event",
 	}
-	return result
 }
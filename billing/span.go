@@ -0,0 +1,104 @@
+package billing
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// spanExtraScale is the number of decimal places added to a decimal
+// quantity when it is split, so that small pieces keep their precision.
+const spanExtraScale = 6
+
+// SplitUsage splits usage that accrued evenly between start and end into one
+// event per period it overlaps, e.g. a job that runs past midnight with
+// DailyPeriod. Each piece gets a share of the quantity proportional to its
+// duration and is timestamped at its start; the last piece absorbs any
+// rounding remainder, so the pieces add up exactly to the total. Pieces
+// carry the idempotency key "<key>-<n>", derived from params.IdempotencyKey
+// (one is generated if it is empty). params.Timestamp is ignored.
+//
+// A nil period selects MonthlyPeriod.
+func SplitUsage(params TrackEventParams, start, end time.Time, period Period) ([]TrackEventParams, error) {
+	if end.Before(start) {
+		verr := &ValidationError{}
+		verr.add("end", "must not be before start")
+		return nil, verr
+	}
+	if period == nil {
+		period = MonthlyPeriod
+	}
+	key := params.IdempotencyKey
+	if key == "" {
+		key = newEventID()
+	}
+
+	// Boundaries of the pieces: start, every period end in between, end
+	bounds := []time.Time{start}
+	for t := start; ; {
+		_, next := period.Bounds(t)
+		if !next.After(t) || !next.Before(end) {
+			break
+		}
+		bounds = append(bounds, next)
+		t = next
+	}
+	bounds = append(bounds, end)
+
+	total := end.Sub(start)
+	pieces := make([]TrackEventParams, 0, len(bounds)-1)
+	var sum float64
+	var sumDecimal Decimal
+	scale := int32(0)
+	if params.QuantityDecimal != nil {
+		scale = params.QuantityDecimal.scale + spanExtraScale
+	}
+
+	for i := 0; i < len(bounds)-1; i++ {
+		piece := params
+		ts := bounds[i].UTC()
+		piece.Timestamp = &ts
+		piece.IdempotencyKey = key + "-" + strconv.Itoa(i+1)
+		last := i == len(bounds)-2
+
+		if d := params.QuantityDecimal; d != nil {
+			var q Decimal
+			if last {
+				q = d.Sub(sumDecimal)
+			} else {
+				q = d.mulRatio(int64(bounds[i+1].Sub(bounds[i])), int64(total), scale)
+			}
+			sumDecimal = sumDecimal.Add(q)
+			q = q.trimmed()
+			piece.QuantityDecimal = &q
+		} else {
+			if last {
+				piece.Quantity = params.Quantity - sum
+			} else {
+				piece.Quantity = params.Quantity * float64(bounds[i+1].Sub(bounds[i])) / float64(total)
+			}
+			sum += piece.Quantity
+		}
+		pieces = append(pieces, piece)
+	}
+	return pieces, nil
+}
+
+// TrackSpan tracks usage that accrued evenly between start and end, split
+// at the boundaries of period with SplitUsage. It tracks every piece and
+// returns the errors of those that failed.
+func (s *SDK) TrackSpan(ctx context.Context, params TrackEventParams, start, end time.Time, period Period) error {
+	pieces, err := SplitUsage(params, start, end, period)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, piece := range pieces {
+		if _, err := s.Track(ctx, piece); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
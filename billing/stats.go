@@ -0,0 +1,129 @@
+package billing
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a point-in-time snapshot of the SDK's state and lifetime
+// counters, returned by SDK.Stats.
+type Stats struct {
+	// QueueLength is the number of events waiting to be flushed
+	QueueLength int
+
+	// InFlight is the number of API requests currently being sent
+	InFlight int
+
+	// Tracked is the number of events accepted by Track and TrackImmediate
+	Tracked uint64
+
+	// Sent is the number of events delivered to the API
+	Sent uint64
+
+	// Failed is the number of events the API rejected, or that failed
+	// immediate delivery
+	Failed uint64
+
+	// Filtered is the number of events skipped by the customer filter
+	Filtered uint64
+
+	// SampledOut is the number of events skipped by per-meter sampling
+	SampledOut uint64
+
+	// Dropped is the number of batched events discarded after failing
+	// transiently for longer than MaxFlushAttempts or MaxEventAge allow
+	Dropped uint64
+
+	// Retried is the number of retry attempts
+	Retried uint64
+
+	// Requeued is the number of times an event was put back into the queue
+	Requeued uint64
+
+	// LastFlush is when the last flush completed (zero if none has)
+	LastFlush time.Time
+
+	// LastFlushResult is the outcome of the last flush (nil if none has)
+	LastFlushResult *BatchResult
+
+	// LastError is the last error returned by an API request (nil if none)
+	LastError error
+
+	// LastErrorTime is when LastError occurred
+	LastErrorTime time.Time
+
+	// CircuitState is the current state of the circuit breaker
+	CircuitState CircuitState
+
+	// RateLimitedUntil is when a pause requested by the API ends (zero or in
+	// the past if requests are not paused)
+	RateLimitedUntil time.Time
+}
+
+// sdkStats holds the counters behind Stats.
+type sdkStats struct {
+	inFlight   atomic.Int64
+	tracked    atomic.Uint64
+	sent       atomic.Uint64
+	failed     atomic.Uint64
+	filtered   atomic.Uint64
+	sampledOut atomic.Uint64
+	dropped    atomic.Uint64
+	retried    atomic.Uint64
+	requeued   atomic.Uint64
+
+	mu              sync.Mutex
+	lastFlush       time.Time
+	lastFlushResult *BatchResult
+	lastErr         error
+	lastErrTime     time.Time
+}
+
+func (st *sdkStats) recordError(err error) {
+	st.mu.Lock()
+	st.lastErr = err
+	st.lastErrTime = time.Now()
+	st.mu.Unlock()
+}
+
+func (st *sdkStats) recordFlush(result *BatchResult) {
+	snapshot := *result
+	snapshot.Errors = append([]BatchError(nil), result.Errors...)
+
+	st.mu.Lock()
+	st.lastFlush = time.Now()
+	st.lastFlushResult = &snapshot
+	st.mu.Unlock()
+}
+
+// Stats returns a snapshot of the SDK's queue, counters and health. It is
+// cheap enough to be polled by metrics exporters.
+func (s *SDK) Stats() Stats {
+	stats := Stats{
+		QueueLength:      s.queue.len(),
+		InFlight:         int(s.stats.inFlight.Load()),
+		Tracked:          s.stats.tracked.Load(),
+		Sent:             s.stats.sent.Load(),
+		Failed:           s.stats.failed.Load(),
+		Filtered:         s.stats.filtered.Load(),
+		SampledOut:       s.stats.sampledOut.Load(),
+		Dropped:          s.stats.dropped.Load(),
+		Retried:          s.stats.retried.Load(),
+		Requeued:         s.stats.requeued.Load(),
+		CircuitState:     s.breaker.current(),
+		RateLimitedUntil: s.limiter.blockedUntil(),
+	}
+
+	s.stats.mu.Lock()
+	stats.LastFlush = s.stats.lastFlush
+	if s.stats.lastFlushResult != nil {
+		result := *s.stats.lastFlushResult
+		stats.LastFlushResult = &result
+	}
+	stats.LastError = s.stats.lastErr
+	stats.LastErrorTime = s.stats.lastErrTime
+	s.stats.mu.Unlock()
+
+	return stats
+}
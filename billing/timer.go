@@ -0,0 +1,231 @@
+package billing
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Timer measures elapsed time for a meter and customer and tracks it as
+// usage. It is created with SDK.StartTimer and is safe for concurrent use.
+//
+// Every event a timer sends carries the idempotency key "<id>-<seq>". A
+// report that fails transiently is resent with the same key and quantity
+// before any newer time is reported, so a retried heartbeat or stop is never
+// counted twice.
+type Timer struct {
+	sdk       *SDK
+	meter     *Meter
+	customer  string
+	unit      time.Duration
+	heartbeat time.Duration
+	id        string
+
+	mu        sync.Mutex
+	running   bool
+	stopped   bool
+	started   time.Time     // start of the current running span
+	pending   time.Duration // elapsed time not yet tracked
+	total     time.Duration // elapsed time in completed spans
+	seq       int
+	failed    *timerReport // last report, if it failed transiently
+	stopBeats chan struct{}
+	beatsDone chan struct{}
+}
+
+// timerReport is elapsed time reported under one idempotency key.
+type timerReport struct {
+	key     string
+	elapsed time.Duration
+}
+
+// TimerOption configures a Timer.
+type TimerOption func(*Timer)
+
+// WithTimerUnit sets the unit of the tracked quantity, e.g. time.Minute to
+// track minutes (default: time.Second). Quantities are fractional.
+func WithTimerUnit(unit time.Duration) TimerOption {
+	return func(t *Timer) {
+		if unit > 0 {
+			t.unit = unit
+		}
+	}
+}
+
+// WithHeartbeat tracks the time elapsed so far every interval while the
+// timer runs, so a crash loses at most one interval of usage.
+func WithHeartbeat(interval time.Duration) TimerOption {
+	return func(t *Timer) {
+		t.heartbeat = interval
+	}
+}
+
+// StartTimer starts a timer that tracks elapsed time for customer on meter
+// when it is stopped, and on every heartbeat if configured. A timer started
+// after Shutdown tracks nothing; its Stop returns ErrClosed.
+func (s *SDK) StartTimer(meter *Meter, customer string, opts ...TimerOption) *Timer {
+	t := &Timer{
+		sdk:      s,
+		meter:    meter,
+		customer: customer,
+		unit:     time.Second,
+		id:       newEventID(),
+		running:  true,
+		started:  time.Now(),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(t)
+		}
+	}
+
+	// After Shutdown the timer has no heartbeat, and Stop returns ErrClosed
+	if t.heartbeat > 0 && s.spawn() {
+		t.stopBeats = make(chan struct{})
+		t.beatsDone = make(chan struct{})
+		go t.beat()
+	}
+	return t
+}
+
+// ID returns the prefix of the idempotency keys of the timer's events.
+func (t *Timer) ID() string {
+	return t.id
+}
+
+// Elapsed returns the time the timer has been running, excluding pauses.
+func (t *Timer) Elapsed() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	elapsed := t.total
+	if t.running {
+		elapsed += time.Since(t.started)
+	}
+	return elapsed
+}
+
+// Pause stops the clock until Resume is called.
+func (t *Timer) Pause() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.running {
+		t.settle(time.Now())
+		t.running = false
+	}
+}
+
+// Resume restarts the clock after Pause.
+func (t *Timer) Resume() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.running && !t.stopped {
+		t.running = true
+		t.started = time.Now()
+	}
+}
+
+// Stop stops the timer and tracks the elapsed time not yet sent by a
+// heartbeat. Calling Stop again retries a report that failed transiently
+// and has no effect otherwise.
+func (t *Timer) Stop(ctx context.Context) error {
+	t.mu.Lock()
+	if t.stopped {
+		failed := t.failed != nil
+		t.mu.Unlock()
+		if failed {
+			return t.report(ctx)
+		}
+		return nil
+	}
+	if t.running {
+		t.settle(time.Now())
+		t.running = false
+	}
+	t.stopped = true
+	t.mu.Unlock()
+
+	// Wait for a running heartbeat so the last report comes after it
+	if t.stopBeats != nil {
+		close(t.stopBeats)
+		<-t.beatsDone
+	}
+	return t.report(ctx)
+}
+
+// settle moves the current running span into pending and total. The caller
+// must hold t.mu.
+func (t *Timer) settle(now time.Time) {
+	span := now.Sub(t.started)
+	t.pending += span
+	t.total += span
+	t.started = now
+}
+
+// report resends a report that failed transiently, then tracks the pending
+// elapsed time as one event under the next sequence number.
+func (t *Timer) report(ctx context.Context) error {
+	t.mu.Lock()
+	retry := t.failed
+	t.failed = nil
+	t.mu.Unlock()
+
+	if retry != nil {
+		if err := t.send(ctx, *retry); err != nil {
+			return err
+		}
+	}
+
+	t.mu.Lock()
+	if t.running {
+		t.settle(time.Now())
+	}
+	if t.pending <= 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	t.seq++
+	r := timerReport{key: t.id + "-" + strconv.Itoa(t.seq), elapsed: t.pending}
+	t.pending = 0
+	t.mu.Unlock()
+
+	return t.send(ctx, r)
+}
+
+// send tracks r. If it fails transiently, r is kept to be resent unchanged,
+// as the event may have reached the API.
+func (t *Timer) send(ctx context.Context, r timerReport) error {
+	params := t.meter.params(t.customer, float64(r.elapsed)/float64(t.unit), nil)
+	params.IdempotencyKey = r.key
+
+	_, err := t.sdk.track(ctx, params, t.meter.rule)
+	if err != nil && isTransient(err) {
+		t.mu.Lock()
+		t.failed = &r
+		t.mu.Unlock()
+	}
+	return err
+}
+
+func (t *Timer) beat() {
+	defer t.sdk.wg.Done()
+	defer close(t.beatsDone)
+	ticker := time.NewTicker(t.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := t.report(context.Background()); err != nil {
+				t.sdk.log("Timer %s heartbeat failed: %v", t.id, err)
+			}
+		case <-t.stopBeats:
+			return
+		case <-t.sdk.stopChan:
+			return
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package billing
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+type contextKey int
+
+const (
+	customerKey contextKey = iota
+	sdkRequestKey
+)
+
+// WithCustomer returns a copy of ctx carrying the customer on whose behalf
+// outbound requests are made. MeteringTransport reads it.
+func WithCustomer(ctx context.Context, customer string) context.Context {
+	return context.WithValue(ctx, customerKey, customer)
+}
+
+// CustomerFromContext returns the customer stored by WithCustomer.
+func CustomerFromContext(ctx context.Context) (string, bool) {
+	customer, ok := ctx.Value(customerKey).(string)
+	return customer, ok && customer != ""
+}
+
+// MeteringTransport is an http.RoundTripper that tracks outbound requests as
+// usage of the customer in the request context (see WithCustomer). Requests
+// without a customer, and requests to the Fluxrate API itself, are passed
+// through without being metered. Metering never delays the response.
+type MeteringTransport struct {
+	// Base sends the requests (default: http.DefaultTransport)
+	Base http.RoundTripper
+
+	// Calls tracks one unit per request that received a response (optional)
+	Calls *Meter
+
+	// StatusMeters tracks one unit per response, keyed by status class:
+	// "1xx" to "5xx" (optional)
+	StatusMeters map[string]*Meter
+
+	// ResponseBytes tracks the size of response bodies when they are
+	// closed (optional)
+	ResponseBytes *Meter
+
+	// ResponseByteUnit is the unit of ResponseBytes (default: Bytes)
+	ResponseByteUnit ByteUnit
+}
+
+// RoundTrip sends req through Base and tracks the configured meters.
+func (t *MeteringTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	customer, ok := CustomerFromContext(req.Context())
+	if !ok || t.isFluxrateRequest(req) {
+		return base.RoundTrip(req)
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	host := Attr{Key: "host", Value: req.URL.Host}
+	if t.Calls != nil {
+		t.track(t.Calls, customer, host)
+	}
+	if m := t.StatusMeters[statusClass(resp.StatusCode)]; m != nil {
+		t.track(m, customer, host, Attr{Key: "status", Value: resp.StatusCode})
+	}
+	if t.ResponseBytes != nil && resp.Body != nil {
+		resp.Body = &meteredBody{body: resp.Body, t: t, customer: customer}
+	}
+	return resp, nil
+}
+
+// track meters one request without delaying the response.
+func (t *MeteringTransport) track(m *Meter, customer string, attrs ...Attr) {
+	t.send(m, customer, m.params(customer, 1, attrs))
+}
+
+// send tracks params on m without blocking: the event is queued, or sent in
+// the background without batching. It is detached from the request context,
+// so a caller that cancels once it has the response does not lose the
+// event.
+func (t *MeteringTransport) send(m *Meter, customer string, params TrackEventParams) {
+	d := m.sdk.trackAsync(context.Background(), params, m.rule)
+
+	// Events rejected up front fail right away; later failures are
+	// logged by the flush
+	select {
+	case <-d.Done():
+		if _, err := d.Wait(context.Background()); err != nil {
+			m.sdk.log("Failed to meter outbound request for %s: %v", customer, err)
+		}
+	default:
+	}
+}
+
+// meteredBody counts the bytes read from a response body and tracks them on
+// ResponseBytes when the body is closed, without waiting for the API.
+type meteredBody struct {
+	body     io.ReadCloser
+	t        *MeteringTransport
+	customer string
+	n        atomic.Int64
+	once     sync.Once
+}
+
+func (b *meteredBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	if n > 0 {
+		b.n.Add(int64(n))
+	}
+	return n, err
+}
+
+// Close closes the body and tracks the bytes read once.
+func (b *meteredBody) Close() error {
+	err := b.body.Close()
+	b.once.Do(func() {
+		n := b.n.Load()
+		if n == 0 {
+			return
+		}
+		m := b.t.ResponseBytes
+		quantity := b.t.ResponseByteUnit.Convert(n)
+		params := m.params(b.customer, 0, nil)
+		params.QuantityDecimal = &quantity
+		b.t.send(m, b.customer, params)
+	})
+	return err
+}
+
+// isFluxrateRequest reports whether req was sent by an SDK or goes to the
+// API of one of the SDKs behind the meters, so it is never metered.
+func (t *MeteringTransport) isFluxrateRequest(req *http.Request) bool {
+	if req.Context().Value(sdkRequestKey) != nil {
+		return true
+	}
+
+	meters := make([]*Meter, 0, len(t.StatusMeters)+2)
+	meters = append(meters, t.Calls, t.ResponseBytes)
+	for _, m := range t.StatusMeters {
+		meters = append(meters, m)
+	}
+	for _, m := range meters {
+		if m != nil && strings.EqualFold(req.URL.Host, m.sdk.apiHost) {
+			return true
+		}
+	}
+	return false
+}
+
+func statusClass(code int) string {
+	return strconv.Itoa(code/100) + "xx"
+}
@@ -0,0 +1,121 @@
+package billing
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+const defaultMaxFutureSkew = 5 * time.Minute
+
+// FieldError describes a problem with a single field of an event.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// ValidationError is returned by Track when an event is rejected before it
+// is queued or sent. It lists every problem found, not just the first one.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	problems := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		problems[i] = fe.Field + ": " + fe.Message
+	}
+	return "Invalid event: " + strings.Join(problems, "; ")
+}
+
+func (e *ValidationError) add(field, format string, args ...interface{}) {
+	e.Errors = append(e.Errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// MeterRule holds additional validation rules for the events of one meter.
+type MeterRule struct {
+	// MinQuantity is the smallest accepted quantity (optional, 0 = no minimum)
+	MinQuantity float64
+
+	// MaxQuantity is the largest accepted quantity (optional, 0 = no maximum)
+	MaxQuantity float64
+
+	// IntegerOnly rejects fractional quantities
+	IntegerOnly bool
+
+	// RequiredMetadata lists metadata keys every event must carry
+	RequiredMetadata []string
+}
+
+// validate checks params against the built-in rules, the rule configured
+// for its meter and extra (may be nil). It returns a *ValidationError, or
+// nil if the event is valid.
+func (s *SDK) validate(params TrackEventParams, now time.Time, extra *MeterRule) error {
+	verr := &ValidationError{}
+
+	if strings.TrimSpace(params.MeterToken) == "" {
+		verr.add("meter_token", "is required")
+	}
+	if strings.TrimSpace(params.CustomerExternalID) == "" {
+		verr.add("customer_external_id", "is required")
+	}
+
+	if d := params.QuantityDecimal; d != nil {
+		if d.Sign() < 0 {
+			verr.add("quantity", "must not be negative; use Adjust for corrections")
+		}
+	} else {
+		q := params.Quantity
+		switch {
+		case math.IsNaN(q) || math.IsInf(q, 0):
+			verr.add("quantity", "must be a finite number")
+		case q < 0:
+			verr.add("quantity", "must not be negative; use Adjust for corrections")
+		}
+	}
+
+	if params.Timestamp != nil && params.Timestamp.After(now.Add(s.config.MaxFutureSkew)) {
+		verr.add("timestamp", "is more than %v in the future", s.config.MaxFutureSkew)
+	}
+
+	if params.Metadata != nil {
+		if _, err := json.Marshal(params.Metadata); err != nil {
+			verr.add("metadata", "cannot be encoded as JSON: %v", err)
+		}
+	}
+
+	if rule, ok := s.config.MeterRules[params.MeterToken]; ok {
+		rule.check(params, verr)
+	}
+	if extra != nil {
+		extra.check(params, verr)
+	}
+
+	if len(verr.Errors) > 0 {
+		return verr
+	}
+	return nil
+}
+
+func (r MeterRule) check(params TrackEventParams, verr *ValidationError) {
+	q, isInteger := params.Quantity, params.Quantity == math.Trunc(params.Quantity)
+	if d := params.QuantityDecimal; d != nil {
+		q, isInteger = d.Float64(), d.IsInteger()
+	}
+	if r.MinQuantity != 0 && q < r.MinQuantity {
+		verr.add("quantity", "must be at least %v", r.MinQuantity)
+	}
+	if r.MaxQuantity != 0 && q > r.MaxQuantity {
+		verr.add("quantity", "must be at most %v", r.MaxQuantity)
+	}
+	if r.IntegerOnly && !isInteger {
+		verr.add("quantity", "must be a whole number")
+	}
+	for _, key := range r.RequiredMetadata {
+		if _, ok := params.Metadata[key]; !ok {
+			verr.add("metadata."+key, "is required")
+		}
+	}
+}
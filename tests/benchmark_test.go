@@ -3,6 +3,8 @@ package tests
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"sync/atomic"
 	"testing"
@@ -301,3 +303,102 @@ func BenchmarkHighThroughput(b *testing.B) {
 		}
 	})
 }
+
+// The benchmarks below run against a local HTTP server and need no credentials.
+// They measure the cost of Track itself (queueing), not API latency.
+
+func newLocalServer(b *testing.B) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"evt_local","quantity":"1"}`))
+	}))
+	b.Cleanup(server.Close)
+	return server
+}
+
+// BenchmarkLocalTrackParallel measures Track latency and allocations with many
+// goroutines enqueueing at once. The events/sec metric is the ingestion rate
+// across all goroutines.
+func BenchmarkLocalTrackParallel(b *testing.B) {
+	server := newLocalServer(b)
+
+	for _, parallelism := range []int{1, 8, 64} {
+		b.Run(fmt.Sprintf("goroutines=%dxGOMAXPROCS", parallelism), func(b *testing.B) {
+			sdk, _ := billing.New("sk_test_local",
+				billing.WithAPIURL(server.URL),
+				billing.WithBatching(1000, 100*time.Millisecond),
+				billing.WithMaxConcurrentRequests(64),
+			)
+			defer func() {
+				// Drop what is left in the queue instead of sending it
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				sdk.Shutdown(ctx)
+			}()
+
+			ctx := context.Background()
+			params := billing.TrackEventParams{
+				MeterToken:         "meter_local",
+				CustomerExternalID: "bench_user_local",
+				Quantity:           1,
+			}
+
+			b.ReportAllocs()
+			b.SetParallelism(parallelism)
+			b.ResetTimer()
+			start := time.Now()
+
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := sdk.Track(ctx, params); err != nil {
+						b.Fatalf("Track failed: %v", err)
+					}
+				}
+			})
+
+			b.ReportMetric(float64(b.N)/time.Since(start).Seconds(), "events/sec")
+			b.StopTimer()
+		})
+	}
+}
+
+// BenchmarkLocalTrackWithMetadata is BenchmarkLocalTrackParallel with metadata
+// on every event, which adds the cost of validating it.
+func BenchmarkLocalTrackWithMetadata(b *testing.B) {
+	server := newLocalServer(b)
+
+	sdk, _ := billing.New("sk_test_local",
+		billing.WithAPIURL(server.URL),
+		billing.WithBatching(1000, 100*time.Millisecond),
+		billing.WithMaxConcurrentRequests(64),
+	)
+	defer func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		sdk.Shutdown(ctx)
+	}()
+
+	ctx := context.Background()
+	metadata := map[string]interface{}{
+		"endpoint": "/api/data",
+		"method":   "POST",
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			sdk.Track(ctx, billing.TrackEventParams{
+				MeterToken:         "meter_local",
+				CustomerExternalID: "bench_user_local",
+				Quantity:           1,
+				Metadata:           metadata,
+			})
+		}
+	})
+
+	b.ReportMetric(float64(b.N)/time.Since(start).Seconds(), "events/sec")
+	b.StopTimer()
+}
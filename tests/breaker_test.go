@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Fluxratehq/fluxrate-golang-sdk/billing"
+)
+
+// Helper function to create a mock HTTP client whose availability can be switched
+func createSwitchableClient(requestCount *int, down *bool) *http.Client {
+	return &http.Client{
+		Transport: &MockRoundTripper{
+			RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+				*requestCount++
+				if *down {
+					return &http.Response{
+						StatusCode: 503,
+						Status:     "503 Service Unavailable",
+						Body:       io.NopCloser(strings.NewReader(`{"detail":"down"}`)),
+						Header:     make(http.Header),
+					}, nil
+				}
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(strings.NewReader(`{"id":"evt_1"}`)),
+					Header:     make(http.Header),
+				}, nil
+			},
+		},
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	requestCount := 0
+	down := true
+	client := createSwitchableClient(&requestCount, &down)
+
+	var mu sync.Mutex
+	var transitions []string
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(client),
+		billing.WithoutBatching(),
+		billing.WithRetry(billing.NoRetry),
+		billing.WithCircuitBreaker(billing.CircuitBreakerConfig{
+			FailureThreshold: 2,
+			OpenTimeout:      50 * time.Millisecond,
+			OnStateChange: func(from, to billing.CircuitState) {
+				mu.Lock()
+				transitions = append(transitions, from.String()+"->"+to.String())
+				mu.Unlock()
+			},
+		}),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	track := func() error {
+		_, err := sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: "user_1",
+			Quantity:           1,
+		})
+		return err
+	}
+
+	track()
+	track()
+	if sdk.CircuitState() != billing.CircuitOpen {
+		t.Fatalf("Expected circuit to be open, got %v", sdk.CircuitState())
+	}
+
+	if err := track(); !errors.Is(err, billing.ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if requestCount != 2 {
+		t.Errorf("Expected 2 requests while open, got %d", requestCount)
+	}
+
+	down = false
+	time.Sleep(60 * time.Millisecond)
+	if err := track(); err != nil {
+		t.Errorf("Expected trial request to succeed, got %v", err)
+	}
+	if sdk.CircuitState() != billing.CircuitClosed {
+		t.Errorf("Expected circuit to be closed, got %v", sdk.CircuitState())
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := "closed->open,open->half-open,half-open->closed"
+	if got := strings.Join(transitions, ","); got != want {
+		t.Errorf("Expected transitions %s, got %s", want, got)
+	}
+}
+
+func TestCircuitBreakerKeepsEventsQueued(t *testing.T) {
+	requestCount := 0
+	down := true
+	client := createSwitchableClient(&requestCount, &down)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(client),
+		billing.WithBatching(100, time.Hour),
+		billing.WithRetry(billing.NoRetry),
+		billing.WithMaxConcurrentRequests(1),
+		billing.WithCircuitBreaker(billing.CircuitBreakerConfig{
+			FailureThreshold: 1,
+			OpenTimeout:      time.Hour,
+		}),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	for i := 0; i < 5; i++ {
+		sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: "user_1",
+			Quantity:           1,
+		})
+	}
+
+	result, err := sdk.Flush(context.Background())
+	if err != nil {
+		t.Fatalf("Flush error: %v", err)
+	}
+	// The 503 is transient, so the attempted event is requeued as well
+	if result.Failed != 0 || result.Requeued != 5 {
+		t.Errorf("Expected 0 failed and 5 requeued events, got %d failed, %d requeued", result.Failed, result.Requeued)
+	}
+	if sdk.Stats().QueueLength != 5 {
+		t.Errorf("Expected 5 queued events, got %d", sdk.Stats().QueueLength)
+	}
+	if requestCount != 1 {
+		t.Errorf("Expected 1 request before the circuit opened, got %d", requestCount)
+	}
+}
@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Fluxratehq/fluxrate-golang-sdk/billing"
+)
+
+func TestCompression(t *testing.T) {
+	var encodings []string
+	reject := false
+
+	client := &http.Client{
+		Transport: &MockRoundTripper{
+			RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+				encoding := req.Header.Get("Content-Encoding")
+				encodings = append(encodings, encoding)
+
+				if encoding != "" && reject {
+					return &http.Response{
+						StatusCode: 415,
+						Status:     "415 Unsupported Media Type",
+						Body:       io.NopCloser(strings.NewReader(`{"detail":"unsupported encoding"}`)),
+						Header:     make(http.Header),
+					}, nil
+				}
+
+				body := req.Body
+				if encoding == "gzip" {
+					zr, err := gzip.NewReader(req.Body)
+					if err != nil {
+						t.Fatalf("Invalid gzip body: %v", err)
+					}
+					body = zr
+				}
+				var params billing.TrackEventParams
+				if err := json.NewDecoder(body).Decode(&params); err != nil {
+					t.Errorf("Failed to decode request body: %v", err)
+				}
+
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(strings.NewReader(`{"id":"evt_1"}`)),
+					Header:     make(http.Header),
+				}, nil
+			},
+		},
+	}
+
+	largeMetadata := map[string]interface{}{"payload": strings.Repeat("x", 2048)}
+
+	track := func(sdk *billing.SDK, metadata map[string]interface{}) error {
+		_, err := sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: "user_1",
+			Quantity:           1,
+			Metadata:           metadata,
+		})
+		return err
+	}
+
+	t.Run("Large Bodies Are Compressed", func(t *testing.T) {
+		encodings, reject = nil, false
+		sdk, _ := billing.New("sk_test_123",
+			billing.WithHTTPClient(client),
+			billing.WithoutBatching(),
+			billing.WithCompression(billing.GzipCodec{}, 0),
+		)
+		defer sdk.Shutdown(context.Background())
+
+		if err := track(sdk, nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := track(sdk, largeMetadata); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if encodings[0] != "" || encodings[1] != "gzip" {
+			t.Errorf("Expected only the large body to be compressed, got %q", encodings)
+		}
+	})
+
+	t.Run("Falls Back On 415", func(t *testing.T) {
+		encodings, reject = nil, true
+		sdk, _ := billing.New("sk_test_123",
+			billing.WithHTTPClient(client),
+			billing.WithoutBatching(),
+			billing.WithCompression(billing.GzipCodec{}, 0),
+		)
+		defer sdk.Shutdown(context.Background())
+
+		if err := track(sdk, largeMetadata); err != nil {
+			t.Fatalf("Expected fallback to succeed, got %v", err)
+		}
+		if err := track(sdk, largeMetadata); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		want := []string{"gzip", "", ""}
+		if strings.Join(encodings, ",") != strings.Join(want, ",") {
+			t.Errorf("Expected encodings %q, got %q", want, encodings)
+		}
+	})
+}
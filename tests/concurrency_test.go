@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Fluxratehq/fluxrate-golang-sdk/billing"
+)
+
+// Helper function to create a slow mock HTTP client that records the peak number of concurrent requests
+func createConcurrencyClient(total, inFlight, peak *int64) *http.Client {
+	return &http.Client{
+		Transport: &MockRoundTripper{
+			RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+				atomic.AddInt64(total, 1)
+				current := atomic.AddInt64(inFlight, 1)
+				for {
+					old := atomic.LoadInt64(peak)
+					if current <= old || atomic.CompareAndSwapInt64(peak, old, current) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt64(inFlight, -1)
+
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(strings.NewReader(`{"id":"evt_1"}`)),
+					Header:     make(http.Header),
+				}, nil
+			},
+		},
+	}
+}
+
+func TestMaxConcurrentRequests(t *testing.T) {
+	var total, inFlight, peak int64
+	client := createConcurrencyClient(&total, &inFlight, &peak)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(client),
+		billing.WithBatching(1000, time.Hour),
+		billing.WithMaxConcurrentRequests(3),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	for i := 0; i < 30; i++ {
+		sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: fmt.Sprintf("user_%d", i),
+			Quantity:           1,
+		})
+	}
+
+	// Immediate sends share the same pool as flushes
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sdk.TrackImmediate(context.Background(), billing.TrackEventParams{
+				MeterToken:         "meter_123",
+				CustomerExternalID: "user_immediate",
+				Quantity:           1,
+			})
+		}()
+	}
+
+	// Overlapping flushes must not send an event twice
+	results := make(chan *billing.BatchResult, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, _ := sdk.Flush(context.Background())
+			results <- result
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	successful := 0
+	for result := range results {
+		successful += result.Successful
+	}
+	if successful != 30 {
+		t.Errorf("Expected 30 events flushed once, got %d", successful)
+	}
+	if total != 35 {
+		t.Errorf("Expected 35 requests, got %d", total)
+	}
+	if peak > 3 {
+		t.Errorf("Expected at most 3 concurrent requests, got %d", peak)
+	}
+}
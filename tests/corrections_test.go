@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Fluxratehq/fluxrate-golang-sdk/billing"
+)
+
+func TestAdjust(t *testing.T) {
+	var paths []string
+	var bodies []map[string]interface{}
+	recorder := createRecordingClient(&bodies, `{"id":"evt_adj_1","quantity":"-120"}`)
+	client := &http.Client{
+		Transport: &MockRoundTripper{
+			RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+				paths = append(paths, req.URL.Path)
+				return recorder.Transport.RoundTrip(req)
+			},
+		},
+	}
+
+	sdk, _ := billing.New("sk_test_123", billing.WithHTTPClient(client))
+	defer sdk.Shutdown(context.Background())
+
+	resp, err := sdk.Adjust(context.Background(), billing.AdjustmentParams{
+		MeterToken:         "meter_requests",
+		CustomerExternalID: "user_1",
+		Quantity:           -120,
+		Reason:             "double-counted requests",
+		EventID:            "evt_42",
+	})
+	if err != nil {
+		t.Fatalf("Adjust error: %v", err)
+	}
+	if resp.ID != "evt_adj_1" {
+		t.Errorf("Expected response evt_adj_1, got %s", resp.ID)
+	}
+
+	// Adjustments are sent right away, even with batching enabled
+	if len(bodies) != 1 || paths[0] != "/api/v1/sdk/adjustments" {
+		t.Fatalf("Expected 1 request to /sdk/adjustments, got %v", paths)
+	}
+	body := bodies[0]
+	if body["quantity"] != float64(-120) || body["reason"] != "double-counted requests" || body["event_id"] != "evt_42" {
+		t.Errorf("Unexpected adjustment body: %v", body)
+	}
+	if key, _ := body["idempotency_key"].(string); key == "" {
+		t.Error("Expected a generated idempotency key")
+	}
+	metadata, _ := body["metadata"].(map[string]interface{})
+	if metadata[billing.CorrectionKey] != "adjustment" || metadata[billing.CorrectionReasonKey] != "double-counted requests" {
+		t.Errorf("Expected correction tags, got %v", metadata)
+	}
+}
+
+func TestAdjustValidation(t *testing.T) {
+	sdk, _ := billing.New("sk_test_123")
+	defer sdk.Shutdown(context.Background())
+
+	_, err := sdk.Adjust(context.Background(), billing.AdjustmentParams{
+		MeterToken:         "meter_requests",
+		CustomerExternalID: "user_1",
+	})
+	var verr *billing.ValidationError
+	if !errors.As(err, &verr) || len(verr.Errors) != 2 {
+		t.Errorf("Expected errors for quantity and reason, got %v", err)
+	}
+
+	err = sdk.VoidEvent(context.Background(), billing.VoidParams{MeterToken: "meter_requests", Reason: "duplicate"})
+	if !errors.As(err, &verr) {
+		t.Errorf("Expected *ValidationError without an event reference, got %v", err)
+	}
+}
+
+func TestVoidEvent(t *testing.T) {
+	var paths []string
+	client := &http.Client{
+		Transport: &MockRoundTripper{
+			RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+				paths = append(paths, req.URL.Path)
+				if strings.Contains(req.URL.Path, "evt_missing") {
+					return &http.Response{
+						StatusCode: 404,
+						Status:     "404 Not Found",
+						Body:       io.NopCloser(strings.NewReader(`{"detail":"not found"}`)),
+						Header:     make(http.Header),
+					}, nil
+				}
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(strings.NewReader(`{}`)),
+					Header:     make(http.Header),
+				}, nil
+			},
+		},
+	}
+
+	sdk, _ := billing.New("sk_test_123", billing.WithHTTPClient(client))
+	defer sdk.Shutdown(context.Background())
+
+	if err := sdk.VoidEvent(context.Background(), billing.VoidParams{EventID: "evt_42", Reason: "duplicate"}); err != nil {
+		t.Errorf("VoidEvent error: %v", err)
+	}
+	err := sdk.VoidEvent(context.Background(), billing.VoidParams{
+		MeterToken:     "meter_requests",
+		IdempotencyKey: "order_42",
+		Reason:         "duplicate",
+	})
+	if err != nil {
+		t.Errorf("VoidEvent by idempotency key error: %v", err)
+	}
+	err = sdk.VoidEvent(context.Background(), billing.VoidParams{EventID: "evt_missing", Reason: "duplicate"})
+	if !errors.Is(err, billing.ErrEventNotFound) {
+		t.Errorf("Expected ErrEventNotFound, got %v", err)
+	}
+
+	want := []string{"/api/v1/sdk/events/evt_42/void", "/api/v1/sdk/events/void", "/api/v1/sdk/events/evt_missing/void"}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected requests to %v, got %v", want, paths)
+	}
+}
@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Fluxratehq/fluxrate-golang-sdk/billing"
+)
+
+func TestDecimal(t *testing.T) {
+	t.Run("Parse And Format", func(t *testing.T) {
+		cases := map[string]string{
+			"0":        "0",
+			"12.50":    "12.50",
+			"-0.001":   "-0.001",
+			".5":       "0.5",
+			"1.25e3":   "1250",
+			"1.5e-3":   "0.0015",
+			"+7":       "7",
+			"00012.30": "12.30",
+			"1e1000":   "1" + strings.Repeat("0", 1000),
+		}
+		for in, want := range cases {
+			d, err := billing.ParseDecimal(in)
+			if err != nil {
+				t.Errorf("ParseDecimal(%q) failed: %v", in, err)
+				continue
+			}
+			if got := d.String(); got != want {
+				t.Errorf("ParseDecimal(%q) = %s, want %s", in, got, want)
+			}
+		}
+
+		for _, in := range []string{"", ".", "-", "1.2.3", "abc", "1e", "NaN", "1e1001", "1e-1001", "1e2000000000", "1e-2000000000", "1e99999999999"} {
+			if _, err := billing.ParseDecimal(in); err == nil {
+				t.Errorf("Expected error parsing %q", in)
+			}
+		}
+	})
+
+	t.Run("Exact Arithmetic", func(t *testing.T) {
+		sum := billing.Decimal{}
+		tenth := billing.MustParseDecimal("0.1")
+		for i := 0; i < 10; i++ {
+			sum = sum.Add(tenth)
+		}
+		if sum.Cmp(billing.NewDecimal(1, 0)) != 0 {
+			t.Errorf("Expected 0.1 * 10 to equal 1, got %s", sum)
+		}
+
+		product := billing.MustParseDecimal("1.5").Mul(billing.MustParseDecimal("-0.2"))
+		if product.String() != "-0.30" {
+			t.Errorf("Expected -0.30, got %s", product)
+		}
+		if diff := billing.NewDecimal(5, 0).Sub(billing.MustParseDecimal("5.000")); !diff.IsZero() {
+			t.Errorf("Expected zero difference, got %s", diff)
+		}
+		if !billing.MustParseDecimal("3.000").IsInteger() || billing.MustParseDecimal("3.01").IsInteger() {
+			t.Error("IsInteger returned wrong result")
+		}
+	})
+
+	t.Run("JSON Round Trip", func(t *testing.T) {
+		d := billing.MustParseDecimal("123456789012345678901234567890.000000001")
+		data, err := json.Marshal(d)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		if string(data) != d.String() {
+			t.Errorf("Expected %s, got %s", d, data)
+		}
+
+		var fromNumber, fromString billing.Decimal
+		if err := json.Unmarshal(data, &fromNumber); err != nil {
+			t.Fatalf("Unmarshal number failed: %v", err)
+		}
+		if err := json.Unmarshal([]byte(`"`+d.String()+`"`), &fromString); err != nil {
+			t.Fatalf("Unmarshal string failed: %v", err)
+		}
+		if fromNumber.Cmp(d) != 0 || fromString.Cmp(d) != 0 {
+			t.Errorf("Round trip mismatch: %s, %s", fromNumber, fromString)
+		}
+	})
+}
+
+func TestTrackDecimalQuantity(t *testing.T) {
+	var sentQuantity string
+	client := &http.Client{
+		Transport: &MockRoundTripper{
+			RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+				var body map[string]json.RawMessage
+				json.NewDecoder(req.Body).Decode(&body)
+				sentQuantity = string(body["quantity"])
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(strings.NewReader(`{"id":"evt_1","quantity":"0.100000000000000000001"}`)),
+					Header:     make(http.Header),
+				}, nil
+			},
+		},
+	}
+
+	sdk, _ := billing.NewSDK(billing.Config{
+		APIKey:     "sk_test_123",
+		HTTPClient: client,
+	})
+	defer sdk.Shutdown(context.Background())
+
+	quantity := billing.MustParseDecimal("0.100000000000000000001")
+	resp, err := sdk.Track(context.Background(), billing.TrackEventParams{
+		MeterToken:         "meter_123",
+		CustomerExternalID: "user_1",
+		QuantityDecimal:    &quantity,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if sentQuantity != "0.100000000000000000001" {
+		t.Errorf("Expected lossless quantity on the wire, got %s", sentQuantity)
+	}
+
+	got, err := resp.QuantityDecimal()
+	if err != nil {
+		t.Fatalf("QuantityDecimal failed: %v", err)
+	}
+	if got.Cmp(quantity) != 0 {
+		t.Errorf("Expected response quantity %s, got %s", quantity, got)
+	}
+}
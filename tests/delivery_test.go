@@ -0,0 +1,163 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Fluxratehq/fluxrate-golang-sdk/billing"
+)
+
+func TestTrackAsync(t *testing.T) {
+	var bodies []map[string]interface{}
+	httpClient := createRecordingClient(&bodies, `{"id":"evt_1"}`)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(httpClient),
+		billing.WithBatching(100, time.Hour),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	delivery := sdk.TrackAsync(context.Background(), billing.TrackEventParams{
+		MeterToken:         "meter_123",
+		CustomerExternalID: "user_1",
+		Quantity:           1,
+	})
+	if len(delivery.ID()) != 32 {
+		t.Errorf("Expected a generated 32 character ID, got %q", delivery.ID())
+	}
+
+	select {
+	case <-delivery.Done():
+		t.Fatal("Expected delivery to be pending until the batch is flushed")
+	default:
+	}
+
+	sdk.Flush(context.Background())
+
+	resp, err := delivery.Wait(context.Background())
+	if err != nil {
+		t.Fatalf("Expected delivery to succeed, got %v", err)
+	}
+	if resp == nil || resp.ID != "evt_1" {
+		t.Errorf("Expected response evt_1, got %+v", resp)
+	}
+
+	if body := bodies[0]; body["idempotency_key"] != delivery.ID() {
+		t.Errorf("Expected idempotency key %s, got %v", delivery.ID(), body["idempotency_key"])
+	}
+}
+
+func TestTrackAsyncKeepsIdempotencyKey(t *testing.T) {
+	requestCount := 0
+	httpClient := createMockClient(t, &requestCount)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(httpClient),
+		billing.WithoutBatching(),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	delivery := sdk.TrackAsync(context.Background(), billing.TrackEventParams{
+		MeterToken:         "meter_123",
+		CustomerExternalID: "user_1",
+		Quantity:           1,
+		IdempotencyKey:     "order_42",
+	})
+	if delivery.ID() != "order_42" {
+		t.Errorf("Expected ID order_42, got %s", delivery.ID())
+	}
+	if _, err := delivery.Wait(context.Background()); err != nil {
+		t.Errorf("Expected delivery to succeed, got %v", err)
+	}
+	if requestCount != 1 {
+		t.Errorf("Expected 1 request, got %d", requestCount)
+	}
+}
+
+func TestTrackAsyncBoundsSenders(t *testing.T) {
+	var requestCount int64
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(createSlowClient(&requestCount, 10*time.Millisecond)),
+		billing.WithoutBatching(),
+		billing.WithMaxConcurrentRequests(2),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	before := runtime.NumGoroutine()
+	deliveries := make([]*billing.Delivery, 100)
+	for i := range deliveries {
+		deliveries[i] = sdk.TrackAsync(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: "user_1",
+			Quantity:           1,
+		})
+	}
+
+	// Events wait in a queue instead of a goroutine each
+	if n := runtime.NumGoroutine() - before; n > 10 {
+		t.Errorf("Expected a bounded number of senders, got %d new goroutines", n)
+	}
+	for _, d := range deliveries {
+		if _, err := d.Wait(context.Background()); err != nil {
+			t.Fatalf("Expected delivery to succeed, got %v", err)
+		}
+	}
+	if n := atomic.LoadInt64(&requestCount); n != 100 {
+		t.Errorf("Expected 100 requests, got %d", n)
+	}
+}
+
+func TestTrackAsyncErrors(t *testing.T) {
+	requestCount := 0
+	down := true
+	client := createSwitchableClient(&requestCount, &down)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(client),
+		billing.WithBatching(100, time.Hour),
+		billing.WithRetry(billing.NoRetry),
+		billing.WithCircuitBreaker(billing.CircuitBreakerConfig{Disabled: true}),
+	)
+
+	invalid := sdk.TrackAsync(context.Background(), billing.TrackEventParams{
+		CustomerExternalID: "user_1",
+		Quantity:           1,
+	})
+	var verr *billing.ValidationError
+	if _, err := invalid.Wait(context.Background()); !errors.As(err, &verr) {
+		t.Errorf("Expected *ValidationError, got %v", err)
+	}
+
+	pending := sdk.TrackAsync(context.Background(), billing.TrackEventParams{
+		MeterToken:         "meter_123",
+		CustomerExternalID: "user_1",
+		Quantity:           1,
+	})
+
+	// A transient failure requeues the event and keeps the delivery pending
+	sdk.Flush(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := pending.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected delivery to be pending, got %v", err)
+	}
+
+	// Shutdown gives up on the event and completes the delivery
+	sdk.Shutdown(context.Background())
+	if _, err := pending.Wait(context.Background()); err == nil {
+		t.Error("Expected delivery to fail after shutdown")
+	}
+
+	closed := sdk.TrackAsync(context.Background(), billing.TrackEventParams{
+		MeterToken:         "meter_123",
+		CustomerExternalID: "user_1",
+		Quantity:           1,
+	})
+	if _, err := closed.Wait(context.Background()); !errors.Is(err, billing.ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Fluxratehq/fluxrate-golang-sdk/billing"
+)
+
+// newEventsServer serves 5 events for user_1 in pages of the requested size
+func newEventsServer(t *testing.T, requests *int) *httptest.Server {
+	events := make([]map[string]interface{}, 5)
+	for i := range events {
+		events[i] = map[string]interface{}{
+			"id":              fmt.Sprintf("evt_%d", i+1),
+			"meter_token":     "meter_123",
+			"quantity":        "1.5",
+			"timestamp":       "2024-12-30T10:00:00Z",
+			"idempotency_key": fmt.Sprintf("order_%d", i+1),
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/api/v1/sdk/events/evt_1":
+			json.NewEncoder(w).Encode(events[0])
+		case "/api/v1/sdk/events/lookup":
+			if q.Get("meter_token") == "meter_123" && q.Get("idempotency_key") == "order_2" {
+				json.NewEncoder(w).Encode(events[1])
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"detail":"not found"}`))
+		case "/api/v1/sdk/events":
+			if q.Get("customer_external_id") != "user_1" || q.Get("metadata[plan]") != "pro" || q.Get("from") == "" {
+				t.Errorf("Unexpected list filters: %v", q)
+			}
+			offset, _ := strconv.Atoi(q.Get("cursor"))
+			limit, _ := strconv.Atoi(q.Get("limit"))
+			end := offset + limit
+			if end > len(events) {
+				end = len(events)
+			}
+			next := ""
+			if end < len(events) {
+				next = strconv.Itoa(end)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": events[offset:end], "next_cursor": next})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"detail":"not found"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestEventsGet(t *testing.T) {
+	requests := 0
+	server := newEventsServer(t, &requests)
+
+	sdk, _ := billing.New("sk_test_123", billing.WithAPIURL(server.URL+"/api/v1"))
+	defer sdk.Shutdown(context.Background())
+
+	event, err := sdk.Events.Get(context.Background(), "evt_1")
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if event.ID != "evt_1" || event.EventTime.IsZero() {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+	if q, _ := event.QuantityDecimal(); q.String() != "1.5" {
+		t.Errorf("Expected quantity 1.5, got %s", q)
+	}
+
+	event, err = sdk.Events.GetByIdempotencyKey(context.Background(), "meter_123", "order_2")
+	if err != nil || event.ID != "evt_2" {
+		t.Errorf("Expected evt_2, got %+v, %v", event, err)
+	}
+
+	if _, err := sdk.Events.Get(context.Background(), "evt_404"); !errors.Is(err, billing.ErrEventNotFound) {
+		t.Errorf("Expected ErrEventNotFound, got %v", err)
+	}
+	if _, err := sdk.Events.GetByIdempotencyKey(context.Background(), "meter_123", "order_404"); !errors.Is(err, billing.ErrEventNotFound) {
+		t.Errorf("Expected ErrEventNotFound, got %v", err)
+	}
+}
+
+func TestEventsList(t *testing.T) {
+	requests := 0
+	server := newEventsServer(t, &requests)
+
+	sdk, _ := billing.New("sk_test_123", billing.WithAPIURL(server.URL+"/api/v1"))
+	defer sdk.Shutdown(context.Background())
+
+	it := sdk.Events.List(billing.ListEventsParams{
+		CustomerExternalID: "user_1",
+		From:               time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC),
+		Metadata:           map[string]string{"plan": "pro"},
+		PageSize:           2,
+	})
+	if requests != 0 {
+		t.Errorf("Expected pages to be fetched lazily, got %d requests", requests)
+	}
+
+	var ids []string
+	for it.Next(context.Background()) {
+		ids = append(ids, it.Item().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("List error: %v", err)
+	}
+	if len(ids) != 5 || ids[0] != "evt_1" || ids[4] != "evt_5" {
+		t.Errorf("Expected evt_1 to evt_5, got %v", ids)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 page requests, got %d", requests)
+	}
+}
@@ -0,0 +1,184 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/Fluxratehq/fluxrate-golang-sdk/billing"
+)
+
+func TestCustomerFilters(t *testing.T) {
+	t.Run("Allow And Deny Lists", func(t *testing.T) {
+		allow := billing.AllowList("cust_1")
+		deny := billing.DenyList("cust_1")
+		if !allow.Allow("cust_1") || allow.Allow("cust_2") {
+			t.Error("AllowList did not match expected customers")
+		}
+		if deny.Allow("cust_1") || !deny.Allow("cust_2") {
+			t.Error("DenyList did not match expected customers")
+		}
+	})
+
+	t.Run("Prefix And Regexp", func(t *testing.T) {
+		prefix := billing.PrefixFilter("beta_")
+		re := billing.RegexpFilter(regexp.MustCompile(`^org_\d+$`))
+		if !prefix.Allow("beta_42") || prefix.Allow("prod_42") {
+			t.Error("PrefixFilter did not match expected customers")
+		}
+		if !re.Allow("org_7") || re.Allow("org_x") {
+			t.Error("RegexpFilter did not match expected customers")
+		}
+	})
+
+	t.Run("Percentage Rollout Is Stable And Monotonic", func(t *testing.T) {
+		small := billing.PercentageRollout(10)
+		large := billing.PercentageRollout(50)
+		allowed := 0
+		for i := 0; i < 10000; i++ {
+			id := fmt.Sprintf("cust_%d", i)
+			if small.Allow(id) {
+				allowed++
+				if !large.Allow(id) {
+					t.Fatalf("Customer %s in 10%% rollout but not in 50%%", id)
+				}
+			}
+		}
+		if allowed < 800 || allowed > 1200 {
+			t.Errorf("Expected roughly 1000 customers in 10%% rollout, got %d", allowed)
+		}
+		if billing.PercentageRollout(0).Allow("cust_1") {
+			t.Error("Expected 0% rollout to allow nobody")
+		}
+		if !billing.PercentageRollout(100).Allow("cust_1") {
+			t.Error("Expected 100% rollout to allow everybody")
+		}
+	})
+}
+
+func TestSetCustomerFilter(t *testing.T) {
+	requestCount := 0
+	httpClient := createMockClient(t, &requestCount)
+
+	sdk, _ := billing.NewSDK(billing.Config{
+		APIKey:     "sk_test_123",
+		HTTPClient: httpClient,
+	})
+	defer sdk.Shutdown(context.Background())
+
+	track := func(customer string) {
+		sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: customer,
+			Quantity:           1,
+		})
+	}
+
+	sdk.SetCustomerFilter(billing.DenyList("cust_blocked"))
+	track("cust_blocked")
+	track("cust_ok")
+	if requestCount != 1 {
+		t.Errorf("Expected 1 request with deny list, got %d", requestCount)
+	}
+
+	sdk.SetCustomerFilter(nil)
+	track("cust_blocked")
+	if requestCount != 2 {
+		t.Errorf("Expected 2 requests after removing filter, got %d", requestCount)
+	}
+}
+
+func TestSetCustomerFilterKeepsAllowedCustomers(t *testing.T) {
+	requestCount := 0
+	httpClient := createMockClient(t, &requestCount)
+
+	sdk, _ := billing.NewSDK(billing.Config{
+		APIKey:           "sk_test_123",
+		HTTPClient:       httpClient,
+		AllowedCustomers: []string{"cust_1", "cust_2"},
+	})
+	defer sdk.Shutdown(context.Background())
+
+	track := func(customer string) {
+		sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: customer,
+			Quantity:           1,
+		})
+	}
+
+	sdk.SetCustomerFilter(billing.DenyList("cust_2"))
+	track("cust_1")
+	track("cust_2")
+	track("cust_3")
+	if requestCount != 1 {
+		t.Errorf("Expected 1 request with allow list and deny list, got %d", requestCount)
+	}
+
+	sdk.SetCustomerFilter(nil)
+	track("cust_2")
+	track("cust_3")
+	if requestCount != 2 {
+		t.Errorf("Expected the allow list to apply after removing the filter, got %d requests", requestCount)
+	}
+}
+
+func TestWatchCustomerFile(t *testing.T) {
+	requestCount := 0
+	httpClient := createMockClient(t, &requestCount)
+
+	path := filepath.Join(t.TempDir(), "customers.txt")
+	if err := os.WriteFile(path, []byte("# rollout cohort\ncust_1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sdk, _ := billing.NewSDK(billing.Config{
+		APIKey:     "sk_test_123",
+		HTTPClient: httpClient,
+	})
+	defer sdk.Shutdown(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := sdk.WatchCustomerFile(ctx, path, 10*time.Millisecond, nil); err != nil {
+		t.Fatalf("WatchCustomerFile failed: %v", err)
+	}
+
+	track := func(customer string) {
+		sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: customer,
+			Quantity:           1,
+		})
+	}
+
+	track("cust_2")
+	if requestCount != 0 {
+		t.Errorf("Expected cust_2 to be filtered, got %d requests", requestCount)
+	}
+
+	if err := os.WriteFile(path, []byte("cust_1\ncust_2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	track("cust_2")
+	if requestCount != 1 {
+		t.Errorf("Expected cust_2 to be tracked after reload, got %d requests", requestCount)
+	}
+
+	if err := sdk.WatchCustomerFile(ctx, filepath.Join(t.TempDir(), "missing.txt"), time.Second, nil); err == nil {
+		t.Error("Expected error for missing customer file")
+	}
+
+	sdk.Shutdown(context.Background())
+	if err := sdk.WatchCustomerFile(ctx, path, time.Second, nil); !errors.Is(err, billing.ErrClosed) {
+		t.Errorf("Expected ErrClosed after shutdown, got %v", err)
+	}
+}
@@ -3,8 +3,10 @@ package tests
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"strings"
 	"testing"
@@ -380,12 +382,17 @@ func TestBatching(t *testing.T) {
 			})
 		}
 
-		// Give it a moment to process the batch
-		time.Sleep(100 * time.Millisecond)
+		// The full batch is flushed in the background; wait for it through
+		// Stats, which is safe to read while the flush runs
+		deadline := time.Now().Add(time.Second)
+		for sdk.Stats().Sent < 2 && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
 
 		// Should have sent 2 events in one batch
-		if requestCount != 2 {
-			t.Errorf("Expected 2 requests (batch flushed), got %d", requestCount)
+		stats := sdk.Stats()
+		if stats.Sent != 2 || stats.QueueLength != 1 {
+			t.Errorf("Expected 2 events sent (batch flushed) and 1 queued, got %d sent, %d queued", stats.Sent, stats.QueueLength)
 		}
 	})
 }
@@ -503,11 +510,86 @@ func TestValidation(t *testing.T) {
 		}
 	})
 
-	// Note: SDK currently doesn't validate parameters client-side
-	// Validation happens on the server side
-	t.Run("Server-Side Validation", func(t *testing.T) {
-		// The SDK will send these requests to the server
-		// and the server will validate them
-		t.Log("SDK performs server-side validation only")
+	t.Run("Invalid Fields Are Rejected Before Sending", func(t *testing.T) {
+		requestCount = 0
+		future := time.Now().Add(time.Hour)
+		_, err := sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "",
+			CustomerExternalID: "",
+			Quantity:           math.NaN(),
+			Timestamp:          &future,
+			Metadata:           map[string]interface{}{"bad": make(chan int)},
+		})
+
+		var verr *billing.ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("Expected *ValidationError, got %v", err)
+		}
+		fields := make(map[string]bool)
+		for _, fe := range verr.Errors {
+			fields[fe.Field] = true
+		}
+		for _, field := range []string{"meter_token", "customer_external_id", "quantity", "timestamp", "metadata"} {
+			if !fields[field] {
+				t.Errorf("Expected error for field %s, got %v", field, verr)
+			}
+		}
+		if requestCount != 0 {
+			t.Errorf("Expected 0 requests for invalid event, got %d", requestCount)
+		}
+	})
+
+	t.Run("Negative And Infinite Quantity", func(t *testing.T) {
+		for _, q := range []float64{-1, math.Inf(1)} {
+			_, err := sdk.TrackImmediate(context.Background(), billing.TrackEventParams{
+				MeterToken:         "meter_123",
+				CustomerExternalID: "user_1",
+				Quantity:           q,
+			})
+			var verr *billing.ValidationError
+			if !errors.As(err, &verr) {
+				t.Errorf("Expected *ValidationError for quantity %v, got %v", q, err)
+			}
+		}
+	})
+
+	t.Run("Per-Meter Rules", func(t *testing.T) {
+		requestCount = 0
+		ruled, _ := billing.New("sk_test_123",
+			billing.WithHTTPClient(httpClient),
+			billing.WithoutBatching(),
+			billing.WithMeterRule("meter_seats", billing.MeterRule{
+				MaxQuantity:      100,
+				IntegerOnly:      true,
+				RequiredMetadata: []string{"plan"},
+			}),
+		)
+		defer ruled.Shutdown(context.Background())
+
+		_, err := ruled.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_seats",
+			CustomerExternalID: "user_1",
+			Quantity:           150.5,
+		})
+		var verr *billing.ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("Expected *ValidationError, got %v", err)
+		}
+		if len(verr.Errors) != 3 {
+			t.Errorf("Expected 3 field errors, got %v", verr)
+		}
+
+		_, err = ruled.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_seats",
+			CustomerExternalID: "user_1",
+			Quantity:           5,
+			Metadata:           map[string]interface{}{"plan": "pro"},
+		})
+		if err != nil {
+			t.Errorf("Unexpected error for valid event: %v", err)
+		}
+		if requestCount != 1 {
+			t.Errorf("Expected 1 request, got %d", requestCount)
+		}
 	})
 }
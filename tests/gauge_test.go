@@ -0,0 +1,194 @@
+package tests
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Fluxratehq/fluxrate-golang-sdk/billing"
+)
+
+func TestGauge(t *testing.T) {
+	var bodies []map[string]interface{}
+	httpClient := createRecordingClient(&bodies, `{"id":"evt_1"}`)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(httpClient),
+		billing.WithoutBatching(),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	bucket := billing.PeriodFunc(func(t time.Time) (time.Time, time.Time) {
+		start := t.Truncate(100 * time.Millisecond)
+		return start, start.Add(100 * time.Millisecond)
+	})
+
+	storage := sdk.NewGauge(sdk.Meter("meter_storage"),
+		billing.WithSampleInterval(10*time.Millisecond),
+		billing.WithBucket(bucket),
+		billing.WithGaugeUnit(100*time.Millisecond),
+	)
+	start := time.Now()
+	storage.Observe("user_1", func(ctx context.Context) (float64, error) {
+		return 2, nil
+	})
+
+	time.Sleep(350 * time.Millisecond)
+	if err := storage.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop error: %v", err)
+	}
+	elapsed := time.Since(start)
+
+	if len(bodies) < 3 {
+		t.Fatalf("Expected an event per bucket, got %d", len(bodies))
+	}
+
+	var total float64
+	keys := make(map[interface{}]bool)
+	for i, body := range bodies {
+		quantity := body["quantity"].(float64)
+		total += quantity
+		keys[body["idempotency_key"]] = true
+
+		// Buckets between the first and the last are complete and aligned
+		if i > 0 && i < len(bodies)-1 {
+			ts, _ := time.Parse(time.RFC3339Nano, body["timestamp"].(string))
+			if !ts.Equal(ts.Truncate(100 * time.Millisecond)) {
+				t.Errorf("Expected bucket %d to start on a boundary, got %v", i, ts)
+			}
+			if math.Abs(quantity-2) > 1e-6 {
+				t.Errorf("Expected 2 units in complete bucket %d, got %v", i, quantity)
+			}
+		}
+	}
+	if len(keys) != len(bodies) {
+		t.Errorf("Expected unique idempotency keys, got %v", keys)
+	}
+
+	// The value is held from the first sample until Stop
+	if want := 2 * float64(elapsed) / float64(100*time.Millisecond); total > want || total < want-0.5 {
+		t.Errorf("Expected about %.2f units in total, got %.2f", want, total)
+	}
+}
+
+func TestGaugeAfterShutdown(t *testing.T) {
+	var bodies []map[string]interface{}
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(createRecordingClient(&bodies, `{"id":"evt_1"}`)),
+		billing.WithoutBatching(),
+	)
+	sdk.Shutdown(context.Background())
+
+	g := sdk.NewGauge(sdk.Meter("meter_storage"), billing.WithSampleInterval(time.Millisecond))
+	g.Observe("user_1", func(ctx context.Context) (float64, error) { return 1, nil })
+	time.Sleep(10 * time.Millisecond)
+	if err := g.Stop(context.Background()); err != nil {
+		t.Errorf("Expected Stop to succeed, got %v", err)
+	}
+	if len(bodies) != 0 {
+		t.Errorf("Expected no events, got %d", len(bodies))
+	}
+}
+
+func TestGaugeResendsFailedBuckets(t *testing.T) {
+	var mu sync.Mutex
+	var reports []report
+	var down atomic.Bool
+	down.Store(true)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(createFlakyClient(&mu, &reports, &down)),
+		billing.WithoutBatching(),
+		billing.WithRetry(billing.NoRetry),
+		billing.WithCircuitBreaker(billing.CircuitBreakerConfig{Disabled: true}),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	bucket := billing.PeriodFunc(func(t time.Time) (time.Time, time.Time) {
+		start := t.Truncate(50 * time.Millisecond)
+		return start, start.Add(50 * time.Millisecond)
+	})
+	storage := sdk.NewGauge(sdk.Meter("meter_storage"),
+		billing.WithSampleInterval(10*time.Millisecond),
+		billing.WithBucket(bucket),
+		billing.WithGaugeUnit(50*time.Millisecond),
+	)
+	storage.Observe("user_1", func(ctx context.Context) (float64, error) {
+		return 2, nil
+	})
+
+	time.Sleep(180 * time.Millisecond)
+	down.Store(false)
+	time.Sleep(100 * time.Millisecond)
+	if err := storage.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	failed := make(map[string]string)
+	sent := make(map[string]string)
+	for _, r := range reports {
+		if !r.ok {
+			failed[r.key] = r.quantity
+			continue
+		}
+		if _, ok := sent[r.key]; ok {
+			t.Errorf("Expected bucket %s to be sent once", r.key)
+		}
+		sent[r.key] = r.quantity
+	}
+	if len(failed) == 0 {
+		t.Fatalf("Expected failed buckets while the API was down, got %+v", reports)
+	}
+	// Buckets closed while the API was down queue behind the failed one.
+	if len(sent) < 5 {
+		t.Errorf("Expected every bucket to be sent after recovery, got %+v", reports)
+	}
+	for key, quantity := range failed {
+		if sent[key] != quantity {
+			t.Errorf("Expected failed bucket %s to be resent with %s, got %q", key, quantity, sent[key])
+		}
+	}
+}
+
+func TestGaugeStopResendsFailedBuckets(t *testing.T) {
+	var mu sync.Mutex
+	var reports []report
+	var down atomic.Bool
+	down.Store(true)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(createFlakyClient(&mu, &reports, &down)),
+		billing.WithoutBatching(),
+		billing.WithRetry(billing.NoRetry),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	storage := sdk.NewGauge(sdk.Meter("meter_storage"), billing.WithSampleInterval(time.Hour))
+	storage.Observe("user_1", func(ctx context.Context) (float64, error) {
+		return 2, nil
+	})
+	time.Sleep(20 * time.Millisecond)
+
+	if err := storage.Stop(context.Background()); err == nil {
+		t.Fatal("Expected Stop to fail while the API is down")
+	}
+	down.Store(false)
+	if err := storage.Stop(context.Background()); err != nil {
+		t.Fatalf("Expected second Stop to resend the bucket, got %v", err)
+	}
+	if err := storage.Stop(context.Background()); err != nil {
+		t.Errorf("Expected third Stop to be a no-op, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reports) != 2 || reports[1] != (report{key: reports[0].key, quantity: reports[0].quantity, ok: true}) {
+		t.Errorf("Expected the same bucket to be resent, got %+v", reports)
+	}
+}
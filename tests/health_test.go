@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Fluxratehq/fluxrate-golang-sdk/billing"
+)
+
+func newPreflightServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			w.Write([]byte(`{"status":"ok"}`))
+			return
+		}
+		if r.Header.Get("X-API-Key") != "sk_test_valid" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"detail":"invalid api key"}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v1/sdk/verify", "/api/v1/sdk/meters/meter_ok":
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"detail":"not found"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPing(t *testing.T) {
+	server := newPreflightServer(t)
+
+	sdk, _ := billing.New("sk_test_revoked", billing.WithAPIURL(server.URL+"/api/v1"))
+	defer sdk.Shutdown(context.Background())
+
+	// Ping only checks reachability, not the key
+	if err := sdk.Ping(context.Background()); err != nil {
+		t.Errorf("Expected ping to succeed, got %v", err)
+	}
+
+	unreachable, _ := billing.New("sk_test_valid", billing.WithAPIURL("http://127.0.0.1:1/api/v1"))
+	defer unreachable.Shutdown(context.Background())
+
+	if err := unreachable.Ping(context.Background()); err == nil {
+		t.Error("Expected ping to fail for unreachable API")
+	}
+}
+
+func TestPreflight(t *testing.T) {
+	server := newPreflightServer(t)
+
+	t.Run("Valid Key And Meters", func(t *testing.T) {
+		sdk, _ := billing.New("sk_test_valid", billing.WithAPIURL(server.URL+"/api/v1"))
+		defer sdk.Shutdown(context.Background())
+
+		if err := sdk.Preflight(context.Background(), "meter_ok"); err != nil {
+			t.Errorf("Expected preflight to succeed, got %v", err)
+		}
+	})
+
+	t.Run("Revoked Key", func(t *testing.T) {
+		sdk, _ := billing.New("sk_test_revoked", billing.WithAPIURL(server.URL+"/api/v1"))
+		defer sdk.Shutdown(context.Background())
+
+		err := sdk.Preflight(context.Background(), "meter_ok")
+		if !errors.Is(err, billing.ErrUnauthorized) {
+			t.Errorf("Expected ErrUnauthorized, got %v", err)
+		}
+	})
+
+	t.Run("Missing Meter", func(t *testing.T) {
+		sdk, _ := billing.New("sk_test_valid", billing.WithAPIURL(server.URL+"/api/v1"))
+		defer sdk.Shutdown(context.Background())
+
+		err := sdk.Preflight(context.Background(), "meter_ok", "meter_missing")
+		var perr *billing.PreflightError
+		if !errors.As(err, &perr) {
+			t.Fatalf("Expected *PreflightError, got %v", err)
+		}
+		if len(perr.Meters) != 1 || !errors.Is(perr.Meters["meter_missing"], billing.ErrMeterNotFound) {
+			t.Errorf("Expected meter_missing to be reported as not found, got %v", perr)
+		}
+	})
+}
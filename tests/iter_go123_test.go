@@ -0,0 +1,35 @@
+//go:build go1.23
+
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fluxratehq/fluxrate-golang-sdk/billing"
+)
+
+func TestIterAll(t *testing.T) {
+	requests := 0
+	server := newOffsetServer(t, 5, false, &requests)
+
+	sdk, _ := billing.New("sk_test_123", billing.WithAPIURL(server.URL+"/api/v1"))
+	defer sdk.Shutdown(context.Background())
+
+	var ids []string
+	for event, err := range sdk.Events.List(billing.ListEventsParams{PageSize: 2}).All(context.Background()) {
+		if err != nil {
+			t.Fatalf("All error: %v", err)
+		}
+		ids = append(ids, event.ID)
+		if len(ids) == 3 {
+			break
+		}
+	}
+	if len(ids) != 3 || ids[2] != "evt_3" {
+		t.Errorf("Expected evt_1 to evt_3, got %v", ids)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Fluxratehq/fluxrate-golang-sdk/billing"
+)
+
+// newOffsetServer serves total events paged by offset. The first request
+// for the second page fails with 503 if flaky is set.
+func newOffsetServer(t *testing.T, total int, flaky bool, requests *int) *httptest.Server {
+	failed := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		if flaky && offset > 0 && !failed {
+			failed = true
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		end := offset + limit
+		if end > total {
+			end = total
+		}
+		data := []map[string]interface{}{}
+		for i := offset; i < end; i++ {
+			data = append(data, map[string]interface{}{"id": fmt.Sprintf("evt_%d", i+1), "quantity": "1"})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data, "has_more": end < total})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestIterOffsetPaging(t *testing.T) {
+	requests := 0
+	server := newOffsetServer(t, 7, true, &requests)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithAPIURL(server.URL+"/api/v1"),
+		billing.WithRetry(billing.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	events, err := sdk.Events.List(billing.ListEventsParams{PageSize: 3}).Collect(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("Collect error: %v", err)
+	}
+	if len(events) != 7 || events[0].ID != "evt_1" || events[6].ID != "evt_7" {
+		t.Errorf("Expected evt_1 to evt_7, got %d events", len(events))
+	}
+	// 3 pages plus one retried request
+	if requests != 4 {
+		t.Errorf("Expected 4 requests, got %d", requests)
+	}
+	if sdk.Stats().Retried != 1 {
+		t.Errorf("Expected 1 retry, got %d", sdk.Stats().Retried)
+	}
+}
+
+func TestIterCollectLimit(t *testing.T) {
+	requests := 0
+	server := newOffsetServer(t, 10, false, &requests)
+
+	sdk, _ := billing.New("sk_test_123", billing.WithAPIURL(server.URL+"/api/v1"))
+	defer sdk.Shutdown(context.Background())
+
+	it := sdk.Events.List(billing.ListEventsParams{PageSize: 3})
+	events, err := it.Collect(context.Background(), 4)
+	if err != nil {
+		t.Fatalf("Collect error: %v", err)
+	}
+	if len(events) != 4 || events[3].ID != "evt_4" {
+		t.Errorf("Expected evt_1 to evt_4, got %d events", len(events))
+	}
+	if requests != 2 {
+		t.Errorf("Expected only the 2 pages needed, got %d requests", requests)
+	}
+
+	// Collecting again continues where the iterator stopped
+	rest, err := it.Collect(context.Background(), 0)
+	if err != nil || len(rest) != 6 || rest[0].ID != "evt_5" {
+		t.Errorf("Expected evt_5 to evt_10, got %d events, %v", len(rest), err)
+	}
+}
+
+func TestIterCancellation(t *testing.T) {
+	requests := 0
+	server := newOffsetServer(t, 10, false, &requests)
+
+	sdk, _ := billing.New("sk_test_123", billing.WithAPIURL(server.URL+"/api/v1"))
+	defer sdk.Shutdown(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	it := sdk.Events.List(billing.ListEventsParams{PageSize: 5})
+	if !it.Next(ctx) {
+		t.Fatalf("Expected a first event, got %v", it.Err())
+	}
+	cancel()
+
+	if it.Next(ctx) {
+		t.Error("Expected Next to stop after cancellation")
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", it.Err())
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+}
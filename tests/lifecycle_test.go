@@ -0,0 +1,379 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Fluxratehq/fluxrate-golang-sdk/billing"
+)
+
+// Helper function to create a mock HTTP client that answers after a delay
+func createSlowClient(requestCount *int64, delay time.Duration) *http.Client {
+	return &http.Client{
+		Transport: &MockRoundTripper{
+			RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+				atomic.AddInt64(requestCount, 1)
+				select {
+				case <-time.After(delay):
+				case <-req.Context().Done():
+					return nil, req.Context().Err()
+				}
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(strings.NewReader(`{"id":"evt_1"}`)),
+					Header:     make(http.Header),
+				}, nil
+			},
+		},
+	}
+}
+
+func TestShutdownIsIdempotent(t *testing.T) {
+	requestCount := 0
+	httpClient := createMockClient(t, &requestCount)
+
+	sdk, _ := billing.New("sk_test_123", billing.WithHTTPClient(httpClient))
+
+	if err := sdk.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected first shutdown to succeed, got %v", err)
+	}
+	if err := sdk.Shutdown(context.Background()); err != nil {
+		t.Errorf("Expected second shutdown to succeed, got %v", err)
+	}
+
+	_, err := sdk.Track(context.Background(), billing.TrackEventParams{
+		MeterToken:         "meter_123",
+		CustomerExternalID: "user_1",
+		Quantity:           1,
+	})
+	if !errors.Is(err, billing.ErrClosed) {
+		t.Errorf("Expected ErrClosed from Track, got %v", err)
+	}
+	if _, err := sdk.Flush(context.Background()); !errors.Is(err, billing.ErrClosed) {
+		t.Errorf("Expected ErrClosed from Flush, got %v", err)
+	}
+}
+
+func TestShutdownWaitsForInFlightCalls(t *testing.T) {
+	var requestCount int64
+	httpClient := createSlowClient(&requestCount, 50*time.Millisecond)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(httpClient),
+		billing.WithoutBatching(),
+	)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := sdk.TrackImmediate(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: "user_1",
+			Quantity:           1,
+		})
+		done <- err
+	}()
+
+	// Give the request time to start
+	for atomic.LoadInt64(&requestCount) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := sdk.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown error: %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected in-flight request to succeed, got %v", err)
+		}
+	default:
+		t.Error("Expected Shutdown to wait for the in-flight request")
+	}
+}
+
+func TestShutdownReturnsUnsentEvents(t *testing.T) {
+	var requestCount int64
+	httpClient := createSlowClient(&requestCount, time.Second)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(httpClient),
+		billing.WithBatching(100, time.Hour),
+		billing.WithRetry(billing.NoRetry),
+		billing.WithMaxConcurrentRequests(1),
+	)
+
+	for i := 0; i < 3; i++ {
+		sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: "user_1",
+			Quantity:           1,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := sdk.Shutdown(ctx)
+	var serr *billing.ShutdownError
+	if !errors.As(err, &serr) {
+		t.Fatalf("Expected *ShutdownError, got %v", err)
+	}
+	if len(serr.Unsent) != 3 {
+		t.Errorf("Expected 3 unsent events, got %d", len(serr.Unsent))
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline error, got %v", serr.Err)
+	}
+}
+
+func TestShutdownKeepsDeadlineErrorWithOpenCircuit(t *testing.T) {
+	requestCount := 0
+	down := true
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(createSwitchableClient(&requestCount, &down)),
+		billing.WithBatching(100, time.Hour),
+		billing.WithRetry(billing.NoRetry),
+		billing.WithCircuitBreaker(billing.CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Hour}),
+	)
+
+	params := billing.TrackEventParams{
+		MeterToken:         "meter_123",
+		CustomerExternalID: "user_1",
+		Quantity:           1,
+	}
+	sdk.TrackImmediate(context.Background(), params)
+	if sdk.CircuitState() != billing.CircuitOpen {
+		t.Fatalf("Expected circuit to be open, got %v", sdk.CircuitState())
+	}
+	sdk.Track(context.Background(), params)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	err := sdk.Shutdown(ctx)
+	var serr *billing.ShutdownError
+	if !errors.As(err, &serr) || len(serr.Unsent) != 1 {
+		t.Fatalf("Expected *ShutdownError with 1 unsent event, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline error, got %v", serr.Err)
+	}
+}
+
+func TestShutdownReturnsEventsOfRunningFlush(t *testing.T) {
+	// Every request fails with 503 after 200ms
+	httpClient := &http.Client{
+		Transport: &MockRoundTripper{
+			RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+				time.Sleep(200 * time.Millisecond)
+				return &http.Response{
+					StatusCode: http.StatusServiceUnavailable,
+					Status:     "503 Service Unavailable",
+					Body:       io.NopCloser(strings.NewReader(`{"detail":"unavailable"}`)),
+					Header:     make(http.Header),
+				}, nil
+			},
+		},
+	}
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(httpClient),
+		billing.WithBatching(2, time.Hour),
+		billing.WithRetry(billing.NoRetry),
+	)
+
+	deliveries := make([]*billing.Delivery, 4)
+	for i := range deliveries {
+		deliveries[i] = sdk.TrackAsync(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: "user_1",
+			Quantity:           1,
+		})
+	}
+
+	// Let the background flush of the first full batch start
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err := sdk.Shutdown(ctx)
+	var serr *billing.ShutdownError
+	if !errors.As(err, &serr) {
+		t.Fatalf("Expected *ShutdownError, got %v", err)
+	}
+	if len(serr.Unsent) != 4 {
+		t.Errorf("Expected 4 unsent events, got %d", len(serr.Unsent))
+	}
+
+	for i, d := range deliveries {
+		select {
+		case <-d.Done():
+			if _, err := d.Wait(context.Background()); err == nil {
+				t.Errorf("Expected delivery %d to fail", i)
+			}
+		default:
+			t.Errorf("Expected delivery %d to be completed by Shutdown", i)
+		}
+	}
+
+	// The abandoned flush must not put its events back into the queue
+	time.Sleep(300 * time.Millisecond)
+	if n := sdk.Stats().QueueLength; n != 0 {
+		t.Errorf("Expected an empty queue after the flush finished, got %d", n)
+	}
+}
+
+func TestRequeuedEventsKeepIdempotencyKey(t *testing.T) {
+	var mu sync.Mutex
+	var reports []report
+	var down atomic.Bool
+	down.Store(true)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(createFlakyClient(&mu, &reports, &down)),
+		billing.WithBatching(100, time.Hour),
+		billing.WithRetry(billing.NoRetry),
+		billing.WithCircuitBreaker(billing.CircuitBreakerConfig{Disabled: true}),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	for i := 0; i < 3; i++ {
+		sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: "user_1",
+			Quantity:           1,
+		})
+	}
+	sdk.Flush(context.Background())
+	down.Store(false)
+	sdk.Flush(context.Background())
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reports) != 6 {
+		t.Fatalf("Expected 6 requests, got %d", len(reports))
+	}
+	failed := make(map[string]bool)
+	for _, r := range reports[:3] {
+		if r.key == "" {
+			t.Fatal("Expected batched events to get an idempotency key")
+		}
+		failed[r.key] = true
+	}
+	for _, r := range reports[3:] {
+		if !failed[r.key] {
+			t.Errorf("Expected requeued events to be resent with their key, got %q", r.key)
+		}
+	}
+	if len(failed) != 3 {
+		t.Errorf("Expected 3 distinct keys, got %v", failed)
+	}
+}
+
+func TestFlushRequeuesTransientFailures(t *testing.T) {
+	requestCount := 0
+	down := true
+	client := createSwitchableClient(&requestCount, &down)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(client),
+		billing.WithBatching(100, time.Hour),
+		billing.WithRetry(billing.NoRetry),
+		billing.WithCircuitBreaker(billing.CircuitBreakerConfig{Disabled: true}),
+		billing.WithRequeue(2, time.Hour),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	for i := 0; i < 3; i++ {
+		sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: "user_1",
+			Quantity:           1,
+		})
+	}
+
+	result, _ := sdk.Flush(context.Background())
+	if result.Failed != 0 || result.Requeued != 3 {
+		t.Errorf("Expected 3 requeued events, got %+v", result)
+	}
+
+	// The second attempt uses up MaxFlushAttempts
+	result, _ = sdk.Flush(context.Background())
+	if result.Dropped != 3 || len(result.Errors) != 3 {
+		t.Errorf("Expected 3 dropped events, got %+v", result)
+	}
+	if n := sdk.Stats().QueueLength; n != 0 {
+		t.Errorf("Expected an empty queue, got %d events", n)
+	}
+}
+
+func TestFlushRequeuesCancelledEvents(t *testing.T) {
+	var requestCount int64
+	httpClient := createSlowClient(&requestCount, 200*time.Millisecond)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(httpClient),
+		billing.WithBatching(100, time.Hour),
+		billing.WithRetry(billing.NoRetry),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	for i := 0; i < 3; i++ {
+		sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: "user_1",
+			Quantity:           1,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	result, _ := sdk.Flush(ctx)
+	if result.Failed != 0 || result.Requeued != 3 {
+		t.Errorf("Expected cancelled events to be requeued, got %+v", result)
+	}
+	if n := sdk.Stats().QueueLength; n != 3 {
+		t.Errorf("Expected 3 queued events, got %d", n)
+	}
+}
+
+func TestFlushReportsPermanentFailures(t *testing.T) {
+	client := &http.Client{
+		Transport: &MockRoundTripper{
+			RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: 422,
+					Status:     "422 Unprocessable Entity",
+					Body:       io.NopCloser(strings.NewReader(`{"detail":"unknown meter"}`)),
+					Header:     make(http.Header),
+				}, nil
+			},
+		},
+	}
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(client),
+		billing.WithBatching(100, time.Hour),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	sdk.Track(context.Background(), billing.TrackEventParams{
+		MeterToken:         "meter_unknown",
+		CustomerExternalID: "user_1",
+		Quantity:           1,
+	})
+
+	result, _ := sdk.Flush(context.Background())
+	if result.Failed != 1 || result.Requeued != 0 {
+		t.Errorf("Expected 1 failed event, got %+v", result)
+	}
+}
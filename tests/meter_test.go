@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Fluxratehq/fluxrate-golang-sdk/billing"
+)
+
+func TestMeter(t *testing.T) {
+	var bodies []map[string]interface{}
+	httpClient := createRecordingClient(&bodies, `{"id":"evt_1"}`)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(httpClient),
+		billing.WithoutBatching(),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	apiCalls := sdk.Meter("meter_api_calls",
+		billing.WithDefaultMetadata(map[string]interface{}{"region": "eu", "tier": "pro"}),
+	)
+	if apiCalls.Token() != "meter_api_calls" {
+		t.Errorf("Expected token meter_api_calls, got %s", apiCalls.Token())
+	}
+
+	err := apiCalls.Add(context.Background(), "user_1", 3, billing.Attr{Key: "tier", Value: "enterprise"})
+	if err != nil {
+		t.Fatalf("Add error: %v", err)
+	}
+
+	if len(bodies) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(bodies))
+	}
+	body := bodies[0]
+	if body["meter_token"] != "meter_api_calls" || body["customer_external_id"] != "user_1" || body["quantity"] != float64(3) {
+		t.Errorf("Unexpected event body: %v", body)
+	}
+	metadata, _ := body["metadata"].(map[string]interface{})
+	if metadata["region"] != "eu" || metadata["tier"] != "enterprise" {
+		t.Errorf("Expected merged metadata with attr override, got %v", metadata)
+	}
+}
+
+func TestMeterSetReading(t *testing.T) {
+	var bodies []map[string]interface{}
+	httpClient := createRecordingClient(&bodies, `{"id":"evt_1"}`)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(httpClient),
+		billing.WithoutBatching(),
+		billing.WithSampling("meter_seats", billing.Sampling{Rate: 0.5, Deterministic: true}),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	seats := sdk.Meter("meter_seats")
+	for i := 0; i < 20; i++ {
+		if err := seats.Set(context.Background(), "user_1", 12, billing.Attr{Key: "plan", Value: "pro"}); err != nil {
+			t.Fatalf("Set error: %v", err)
+		}
+	}
+	if err := seats.Add(context.Background(), "user_1", 4); err != nil {
+		t.Fatalf("Add error: %v", err)
+	}
+
+	if len(bodies) == 0 {
+		t.Fatal("Expected some readings to be sampled in")
+	}
+	for _, body := range bodies {
+		metadata, _ := body["metadata"].(map[string]interface{})
+		reading := metadata[billing.ReadingKey] == true
+		if reading && body["quantity"] != float64(12) {
+			t.Errorf("Expected sampled reading to keep its value, got %v", body["quantity"])
+		}
+		if reading && metadata["plan"] != "pro" {
+			t.Errorf("Expected reading to keep its attrs, got %v", metadata)
+		}
+		if !reading && body["quantity"] != float64(8) {
+			t.Errorf("Expected sampled usage scaled to 8, got %v", body["quantity"])
+		}
+	}
+}
+
+func TestMeterRule(t *testing.T) {
+	requestCount := 0
+	httpClient := createMockClient(t, &requestCount)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(httpClient),
+		billing.WithoutBatching(),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	seats := sdk.Meter("meter_seats", billing.WithRule(billing.MeterRule{IntegerOnly: true}))
+
+	var verr *billing.ValidationError
+	if err := seats.Set(context.Background(), "user_1", 2.5); !errors.As(err, &verr) {
+		t.Errorf("Expected *ValidationError for fractional seats, got %v", err)
+	}
+	if err := seats.Set(context.Background(), "user_1", 3); err != nil {
+		t.Errorf("Expected Set to succeed, got %v", err)
+	}
+	if requestCount != 1 {
+		t.Errorf("Expected 1 request, got %d", requestCount)
+	}
+
+	// The rule is scoped to the meter
+	if _, err := sdk.Track(context.Background(), billing.TrackEventParams{
+		MeterToken:         "meter_other",
+		CustomerExternalID: "user_1",
+		Quantity:           2.5,
+	}); err != nil {
+		t.Errorf("Expected other meters to be unaffected, got %v", err)
+	}
+}
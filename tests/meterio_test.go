@@ -0,0 +1,240 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Fluxratehq/fluxrate-golang-sdk/billing"
+)
+
+func TestByteUnitConvert(t *testing.T) {
+	tests := []struct {
+		unit billing.ByteUnit
+		n    int64
+		want string
+	}{
+		{billing.Bytes, 1500, "1500"},
+		{billing.KB, 1500, "1.5"},
+		{billing.MB, 2500000, "2.5"},
+		{billing.GB, 1000000000, "1"},
+		{billing.KiB, 1536, "1.5"},
+		{billing.MiB, 512 * 1024, "0.5"},
+		{billing.GiB, 3 << 30, "3"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.unit.Convert(tt.n).String(); got != tt.want {
+			t.Errorf("%v.Convert(%d) = %s, want %s", tt.unit, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestMeterReader(t *testing.T) {
+	var bodies []map[string]interface{}
+	httpClient := createRecordingClient(&bodies, `{"id":"evt_1"}`)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(httpClient),
+		billing.WithoutBatching(),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	download := sdk.Meter("meter_download")
+	r := billing.MeterReader(strings.NewReader(strings.Repeat("x", 2500)), download, "user_1",
+		billing.WithByteUnit(billing.KB),
+	)
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		t.Fatalf("Copy error: %v", err)
+	}
+	if len(bodies) != 0 {
+		t.Errorf("Expected no events before Close, got %d", len(bodies))
+	}
+
+	r.Close()
+	r.Close()
+
+	if r.Count() != 2500 {
+		t.Errorf("Expected 2500 bytes counted, got %d", r.Count())
+	}
+	if len(bodies) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(bodies))
+	}
+	if bodies[0]["quantity"] != 2.5 {
+		t.Errorf("Expected quantity 2.5 KB, got %v", bodies[0]["quantity"])
+	}
+}
+
+// waitForReports waits until the API has received n reports and gives the
+// background sender time to finish.
+func waitForReports(t *testing.T, mu *sync.Mutex, reports *[]report, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		got := len(*reports)
+		mu.Unlock()
+		if got >= n {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d reports, got %d", n, got)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+}
+
+func TestMeterWriterReportEvery(t *testing.T) {
+	var mu sync.Mutex
+	var reports []report
+	var down atomic.Bool
+	httpClient := createFlakyClient(&mu, &reports, &down)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(httpClient),
+		billing.WithoutBatching(),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	var buf bytes.Buffer
+	w := billing.MeterWriter(&buf, sdk.Meter("meter_upload"), "user_1", billing.WithReportEvery(1000))
+
+	// Reports at 1200 and 2400 bytes
+	chunk := make([]byte, 400)
+	for i := 1; i <= 6; i++ {
+		w.Write(chunk)
+		if i%3 == 0 {
+			waitForReports(t, &mu, &reports, i/3)
+		}
+	}
+	w.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reports) != 2 {
+		t.Fatalf("Expected 2 events, got %+v", reports)
+	}
+	if reports[0].quantity != "1200" || reports[1].quantity != "1200" || buf.Len() != 2400 {
+		t.Errorf("Expected 2400 bytes tracked and written, got %+v, %d written", reports, buf.Len())
+	}
+	if reports[0].key == reports[1].key {
+		t.Errorf("Expected unique idempotency keys, got %+v", reports)
+	}
+}
+
+func TestMeterWriterReportDoesNotBlock(t *testing.T) {
+	var requests int64
+	httpClient := createSlowClient(&requests, 200*time.Millisecond)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(httpClient),
+		billing.WithoutBatching(),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	w := billing.MeterWriter(io.Discard, sdk.Meter("meter_upload"), "user_1", billing.WithReportEvery(1000))
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		w.Write(make([]byte, 1000))
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Expected writes not to wait for reports, took %v", elapsed)
+	}
+
+	// Reports run one at a time; Close waits for the running one and sends
+	// the rest
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+	if n := atomic.LoadInt64(&requests); n < 1 || n > 2 {
+		t.Errorf("Expected 1 or 2 reports, got %d", n)
+	}
+}
+
+func TestMeterWriterResendsFailedReport(t *testing.T) {
+	var mu sync.Mutex
+	var reports []report
+	var down atomic.Bool
+	down.Store(true)
+	httpClient := createFlakyClient(&mu, &reports, &down)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(httpClient),
+		billing.WithoutBatching(),
+		billing.WithRetry(billing.NoRetry),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	w := billing.MeterWriter(io.Discard, sdk.Meter("meter_upload"), "user_1", billing.WithReportEvery(1000))
+	w.Write(make([]byte, 1200)) // report 1 fails
+	waitForReports(t, &mu, &reports, 1)
+	w.Write(make([]byte, 1200)) // report 1 fails again
+	waitForReports(t, &mu, &reports, 2)
+	down.Store(false)
+	w.Write(make([]byte, 400)) // report 1 is resent, then report 2
+	waitForReports(t, &mu, &reports, 4)
+	w.Close()
+
+	id := func(r report) string { return r.key[strings.LastIndex(r.key, "-"):] }
+	want := []report{
+		{key: "-1", quantity: "1200", ok: false},
+		{key: "-1", quantity: "1200", ok: false},
+		{key: "-1", quantity: "1200", ok: true},
+		{key: "-2", quantity: "1600", ok: true},
+	}
+	mu.Lock()
+	got := make([]report, len(reports))
+	for i, r := range reports {
+		got[i] = report{key: id(r), quantity: r.quantity, ok: r.ok}
+	}
+	mu.Unlock()
+	if len(got) != len(want) {
+		t.Fatalf("Expected reports %+v, got %+v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Report %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestMeterReaderCloseRetriesFailedReport(t *testing.T) {
+	var mu sync.Mutex
+	var reports []report
+	var down atomic.Bool
+	down.Store(true)
+	httpClient := createFlakyClient(&mu, &reports, &down)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(httpClient),
+		billing.WithoutBatching(),
+		billing.WithRetry(billing.NoRetry),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	r := billing.MeterReader(strings.NewReader("hello"), sdk.Meter("meter_download"), "user_1")
+	io.ReadAll(r)
+	if err := r.Close(); err == nil {
+		t.Fatal("Expected Close to fail while the API is down")
+	}
+	down.Store(false)
+	if err := r.Close(); err != nil {
+		t.Fatalf("Expected second Close to resend the report, got %v", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Errorf("Expected third Close to be a no-op, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reports) != 2 || reports[1] != (report{key: reports[0].key, quantity: "5", ok: true}) {
+		t.Errorf("Expected the same report to be resent, got %+v", reports)
+	}
+}
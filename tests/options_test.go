@@ -0,0 +1,160 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Fluxratehq/fluxrate-golang-sdk/billing"
+)
+
+// Helper function to create a mock HTTP client that always fails with a server error
+func createFailingClient(requestCount *int) *http.Client {
+	return &http.Client{
+		Transport: &MockRoundTripper{
+			RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+				*requestCount++
+				return &http.Response{
+					StatusCode: 500,
+					Status:     "500 Internal Server Error",
+					Body:       io.NopCloser(strings.NewReader(`{"detail":"boom"}`)),
+					Header:     make(http.Header),
+				}, nil
+			},
+		},
+	}
+}
+
+func TestNewWithOptions(t *testing.T) {
+	requestCount := 0
+	httpClient := createMockClient(t, &requestCount)
+
+	t.Run("Batching Enabled By Default", func(t *testing.T) {
+		requestCount = 0
+		sdk, err := billing.New("sk_test_123", billing.WithHTTPClient(httpClient))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		defer sdk.Shutdown(context.Background())
+
+		result, err := sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: "user_1",
+			Quantity:           1,
+		})
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if result != nil {
+			t.Error("Expected nil result for batched event")
+		}
+		if requestCount != 0 {
+			t.Errorf("Expected 0 requests before flush, got %d", requestCount)
+		}
+	})
+
+	t.Run("Batching Explicitly Disabled", func(t *testing.T) {
+		requestCount = 0
+		sdk, _ := billing.New("sk_test_123",
+			billing.WithHTTPClient(httpClient),
+			billing.WithoutBatching(),
+		)
+		defer sdk.Shutdown(context.Background())
+
+		result, err := sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: "user_1",
+			Quantity:           1,
+		})
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if result == nil {
+			t.Error("Expected result, got nil")
+		}
+		if requestCount != 1 {
+			t.Errorf("Expected 1 immediate request, got %d", requestCount)
+		}
+	})
+
+	t.Run("Invalid API Key", func(t *testing.T) {
+		_, err := billing.New("invalid_key")
+		if err == nil {
+			t.Error("Expected error for invalid API key prefix")
+		}
+	})
+}
+
+func TestRetryPolicy(t *testing.T) {
+	requestCount := 0
+	failingClient := createFailingClient(&requestCount)
+
+	t.Run("NoRetry Sends Once", func(t *testing.T) {
+		requestCount = 0
+		sdk, _ := billing.New("sk_test_123",
+			billing.WithHTTPClient(failingClient),
+			billing.WithoutBatching(),
+			billing.WithRetry(billing.NoRetry),
+		)
+		defer sdk.Shutdown(context.Background())
+
+		_, err := sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: "user_1",
+			Quantity:           1,
+		})
+		if err == nil {
+			t.Error("Expected error from failing server")
+		}
+		if requestCount != 1 {
+			t.Errorf("Expected 1 request, got %d", requestCount)
+		}
+	})
+
+	t.Run("Custom Attempts", func(t *testing.T) {
+		requestCount = 0
+		sdk, _ := billing.New("sk_test_123",
+			billing.WithHTTPClient(failingClient),
+			billing.WithoutBatching(),
+			billing.WithRetry(billing.RetryPolicy{
+				MaxAttempts:    3,
+				InitialBackoff: time.Millisecond,
+			}),
+		)
+		defer sdk.Shutdown(context.Background())
+
+		_, err := sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: "user_1",
+			Quantity:           1,
+		})
+		if err == nil {
+			t.Error("Expected error from failing server")
+		}
+		if requestCount != 3 {
+			t.Errorf("Expected 3 requests, got %d", requestCount)
+		}
+	})
+
+	t.Run("NewSDK Without EnableRetry Sends Once", func(t *testing.T) {
+		requestCount = 0
+		sdk, _ := billing.NewSDK(billing.Config{
+			APIKey:         "sk_test_123",
+			HTTPClient:     failingClient,
+			EnableBatching: false,
+		})
+		defer sdk.Shutdown(context.Background())
+
+		sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: "user_1",
+			Quantity:           1,
+		})
+		if requestCount != 1 {
+			t.Errorf("Expected 1 request, got %d", requestCount)
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Fluxratehq/fluxrate-golang-sdk/billing"
+)
+
+func TestRateLimit(t *testing.T) {
+	requestCount := 0
+	httpClient := createMockClient(t, &requestCount)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(httpClient),
+		billing.WithoutBatching(),
+		billing.WithRateLimit(20, 1),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: "user_1",
+			Quantity:           1,
+		})
+	}
+
+	// The first request uses the burst, the other 4 wait 50ms each
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond {
+		t.Errorf("Expected requests to be spread over at least 200ms, took %v", elapsed)
+	}
+	if requestCount != 5 {
+		t.Errorf("Expected 5 requests, got %d", requestCount)
+	}
+}
+
+func TestRateLimitHonoursRetryAfter(t *testing.T) {
+	var requestTimes []time.Time
+	client := &http.Client{
+		Transport: &MockRoundTripper{
+			RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+				requestTimes = append(requestTimes, time.Now())
+				if len(requestTimes) == 1 {
+					header := make(http.Header)
+					header.Set("Retry-After", "0.2")
+					return &http.Response{
+						StatusCode: 429,
+						Status:     "429 Too Many Requests",
+						Body:       io.NopCloser(strings.NewReader(`{"detail":"slow down"}`)),
+						Header:     header,
+					}, nil
+				}
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(strings.NewReader(`{"id":"evt_1"}`)),
+					Header:     make(http.Header),
+				}, nil
+			},
+		},
+	}
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(client),
+		billing.WithoutBatching(),
+		billing.WithRetry(billing.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	_, err := sdk.Track(context.Background(), billing.TrackEventParams{
+		MeterToken:         "meter_123",
+		CustomerExternalID: "user_1",
+		Quantity:           1,
+	})
+	if err != nil {
+		t.Fatalf("Expected retry after 429 to succeed, got %v", err)
+	}
+	if len(requestTimes) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(requestTimes))
+	}
+	if gap := requestTimes[1].Sub(requestTimes[0]); gap < 190*time.Millisecond {
+		t.Errorf("Expected retry to wait for Retry-After, waited %v", gap)
+	}
+}
@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Fluxratehq/fluxrate-golang-sdk/billing"
+)
+
+func TestSampling(t *testing.T) {
+	var bodies []map[string]interface{}
+	httpClient := createRecordingClient(&bodies, `{"id":"evt_1"}`)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(httpClient),
+		billing.WithBatching(10000, time.Hour),
+		billing.WithAllowedCustomers("user_1"),
+		billing.WithSampling("meter_views", billing.Sampling{Rate: 0.25}),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	const n = 4000
+	for i := 0; i < n; i++ {
+		sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_views",
+			CustomerExternalID: "user_1",
+			Quantity:           1,
+			Metadata:           map[string]interface{}{"page": "/home"},
+		})
+	}
+	// Filtered customers are skipped before sampling
+	sdk.Track(context.Background(), billing.TrackEventParams{
+		MeterToken:         "meter_views",
+		CustomerExternalID: "user_2",
+		Quantity:           1,
+	})
+	// Other meters are not sampled
+	sdk.Track(context.Background(), billing.TrackEventParams{
+		MeterToken:         "meter_orders",
+		CustomerExternalID: "user_1",
+		Quantity:           1,
+	})
+
+	stats := sdk.Stats()
+	if stats.Filtered != 1 {
+		t.Errorf("Expected 1 filtered event, got %d", stats.Filtered)
+	}
+	if kept := n - int(stats.SampledOut); kept < 800 || kept > 1200 {
+		t.Errorf("Expected about 1000 sampled events, got %d", kept)
+	}
+
+	sdk.Flush(context.Background())
+
+	var total float64
+	for _, body := range bodies {
+		if body["meter_token"] != "meter_views" {
+			if _, ok := body["metadata"]; ok {
+				t.Errorf("Expected unsampled meter to carry no sample rate, got %v", body["metadata"])
+			}
+			continue
+		}
+		total += body["quantity"].(float64)
+		metadata := body["metadata"].(map[string]interface{})
+		if metadata["sample_rate"] != 0.25 || metadata["page"] != "/home" {
+			t.Errorf("Expected sample rate and original metadata, got %v", metadata)
+		}
+	}
+	if total < 0.8*n || total > 1.2*n {
+		t.Errorf("Expected the scaled total to be about %d, got %v", n, total)
+	}
+}
+
+func TestDeterministicSampling(t *testing.T) {
+	requestCount := 0
+	httpClient := createMockClient(t, &requestCount)
+
+	newSDK := func() *billing.SDK {
+		sdk, _ := billing.New("sk_test_123",
+			billing.WithHTTPClient(httpClient),
+			billing.WithBatching(10000, time.Hour),
+			billing.WithSampling("meter_views", billing.Sampling{Rate: 0.5, Deterministic: true}),
+		)
+		return sdk
+	}
+
+	first, second := newSDK(), newSDK()
+	defer first.Shutdown(context.Background())
+	defer second.Shutdown(context.Background())
+
+	for i := 0; i < 200; i++ {
+		params := billing.TrackEventParams{
+			MeterToken:         "meter_views",
+			CustomerExternalID: "user_1",
+			Quantity:           1,
+			IdempotencyKey:     fmt.Sprintf("req_%d", i),
+		}
+		first.Track(context.Background(), params)
+		second.Track(context.Background(), params)
+	}
+
+	a, b := first.Stats().QueueLength, second.Stats().QueueLength
+	if a != b {
+		t.Errorf("Expected both SDKs to keep the same events, got %d and %d", a, b)
+	}
+	if a == 0 || a == 200 {
+		t.Errorf("Expected some events to be sampled out, kept %d", a)
+	}
+}
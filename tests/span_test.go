@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Fluxratehq/fluxrate-golang-sdk/billing"
+)
+
+func TestSplitUsage(t *testing.T) {
+	start := time.Date(2024, 12, 31, 22, 0, 0, 0, time.UTC)
+	end := start.Add(3 * time.Hour)
+
+	pieces, err := billing.SplitUsage(billing.TrackEventParams{
+		MeterToken:         "meter_compute",
+		CustomerExternalID: "user_1",
+		Quantity:           3,
+		IdempotencyKey:     "job_42",
+	}, start, end, billing.DailyPeriod)
+	if err != nil {
+		t.Fatalf("SplitUsage error: %v", err)
+	}
+
+	if len(pieces) != 2 {
+		t.Fatalf("Expected 2 pieces, got %d", len(pieces))
+	}
+	if pieces[0].Quantity != 2 || pieces[1].Quantity != 1 {
+		t.Errorf("Expected quantities 2 and 1, got %v and %v", pieces[0].Quantity, pieces[1].Quantity)
+	}
+	if !pieces[0].Timestamp.Equal(start) || !pieces[1].Timestamp.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected pieces to start at the span start and at midnight, got %v and %v", pieces[0].Timestamp, pieces[1].Timestamp)
+	}
+	if pieces[0].IdempotencyKey != "job_42-1" || pieces[1].IdempotencyKey != "job_42-2" {
+		t.Errorf("Expected derived idempotency keys, got %s and %s", pieces[0].IdempotencyKey, pieces[1].IdempotencyKey)
+	}
+}
+
+func TestSplitUsageDecimal(t *testing.T) {
+	start := time.Date(2024, 12, 30, 10, 0, 0, 0, time.UTC)
+	total := billing.MustParseDecimal("1")
+
+	pieces, err := billing.SplitUsage(billing.TrackEventParams{
+		MeterToken:         "meter_compute",
+		CustomerExternalID: "user_1",
+		QuantityDecimal:    &total,
+	}, start, start.Add(3*time.Hour), billing.HourlyPeriod)
+	if err != nil {
+		t.Fatalf("SplitUsage error: %v", err)
+	}
+
+	want := []string{"0.333333", "0.333333", "0.333334"}
+	sum := billing.NewDecimal(0, 0)
+	for i, piece := range pieces {
+		if got := piece.QuantityDecimal.String(); got != want[i] {
+			t.Errorf("Piece %d: expected %s, got %s", i, want[i], got)
+		}
+		sum = sum.Add(*piece.QuantityDecimal)
+	}
+	if sum.Cmp(total) != 0 {
+		t.Errorf("Expected pieces to add up to %s, got %s", total, sum)
+	}
+
+	var verr *billing.ValidationError
+	if _, err := billing.SplitUsage(billing.TrackEventParams{}, start, start.Add(-time.Hour), nil); !errors.As(err, &verr) {
+		t.Errorf("Expected *ValidationError for an inverted span, got %v", err)
+	}
+}
+
+func TestTrackSpan(t *testing.T) {
+	var bodies []map[string]interface{}
+	httpClient := createRecordingClient(&bodies, `{"id":"evt_1"}`)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(httpClient),
+		billing.WithoutBatching(),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	start := time.Date(2024, 11, 30, 23, 30, 0, 0, time.UTC)
+	err := sdk.TrackSpan(context.Background(), billing.TrackEventParams{
+		MeterToken:         "meter_compute",
+		CustomerExternalID: "user_1",
+		Quantity:           60,
+	}, start, start.Add(time.Hour), billing.MonthlyPeriod)
+	if err != nil {
+		t.Fatalf("TrackSpan error: %v", err)
+	}
+
+	if len(bodies) != 2 || bodies[0]["quantity"] != float64(30) || bodies[1]["quantity"] != float64(30) {
+		t.Errorf("Expected two events of 30 across the month boundary, got %v", bodies)
+	}
+}
@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Fluxratehq/fluxrate-golang-sdk/billing"
+)
+
+func TestStats(t *testing.T) {
+	requestCount := 0
+	httpClient := createMockClient(t, &requestCount)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(httpClient),
+		billing.WithBatching(100, time.Hour),
+		billing.WithAllowedCustomers("user_1"),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	for i := 0; i < 3; i++ {
+		sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: "user_1",
+			Quantity:           1,
+		})
+	}
+	sdk.Track(context.Background(), billing.TrackEventParams{
+		MeterToken:         "meter_123",
+		CustomerExternalID: "user_2",
+		Quantity:           1,
+	})
+
+	stats := sdk.Stats()
+	if stats.QueueLength != 3 || stats.Tracked != 3 || stats.Filtered != 1 {
+		t.Errorf("Expected 3 queued, 3 tracked and 1 filtered, got %+v", stats)
+	}
+	if !stats.LastFlush.IsZero() || stats.LastFlushResult != nil {
+		t.Errorf("Expected no flush yet, got %+v", stats)
+	}
+
+	sdk.Flush(context.Background())
+
+	stats = sdk.Stats()
+	if stats.QueueLength != 0 || stats.Sent != 3 || stats.Failed != 0 {
+		t.Errorf("Expected 3 sent events and an empty queue, got %+v", stats)
+	}
+	if stats.LastFlushResult == nil || stats.LastFlushResult.Successful != 3 {
+		t.Errorf("Expected last flush to report 3 successful events, got %+v", stats.LastFlushResult)
+	}
+	if stats.CircuitState != billing.CircuitClosed {
+		t.Errorf("Expected closed circuit, got %v", stats.CircuitState)
+	}
+}
+
+func TestStatsRecordsFailures(t *testing.T) {
+	requestCount := 0
+	httpClient := createFailingClient(&requestCount)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(httpClient),
+		billing.WithoutBatching(),
+		billing.WithRetry(billing.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	sdk.Track(context.Background(), billing.TrackEventParams{
+		MeterToken:         "meter_123",
+		CustomerExternalID: "user_1",
+		Quantity:           1,
+	})
+
+	stats := sdk.Stats()
+	if stats.Failed != 1 || stats.Retried != 2 {
+		t.Errorf("Expected 1 failed event after 2 retries, got %+v", stats)
+	}
+	if stats.LastError == nil || stats.LastErrorTime.IsZero() {
+		t.Errorf("Expected last error to be recorded, got %+v", stats)
+	}
+	if stats.InFlight != 0 {
+		t.Errorf("Expected no requests in flight, got %d", stats.InFlight)
+	}
+}
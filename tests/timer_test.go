@@ -0,0 +1,245 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Fluxratehq/fluxrate-golang-sdk/billing"
+)
+
+// report is an event received by a flaky client
+type report struct {
+	key      string
+	quantity string
+	ok       bool
+}
+
+// createFlakyClient records every event it receives and answers with 503
+// while down is set.
+func createFlakyClient(mu *sync.Mutex, reports *[]report, down *atomic.Bool) *http.Client {
+	return &http.Client{
+		Transport: &MockRoundTripper{
+			RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+				var body struct {
+					IdempotencyKey string          `json:"idempotency_key"`
+					Quantity       json.RawMessage `json:"quantity"`
+				}
+				data, _ := io.ReadAll(req.Body)
+				json.Unmarshal(data, &body)
+
+				failed := down.Load()
+				mu.Lock()
+				*reports = append(*reports, report{key: body.IdempotencyKey, quantity: string(body.Quantity), ok: !failed})
+				mu.Unlock()
+
+				if failed {
+					return &http.Response{
+						StatusCode: 503,
+						Status:     "503 Service Unavailable",
+						Body:       io.NopCloser(strings.NewReader(`{"detail":"down"}`)),
+						Header:     make(http.Header),
+					}, nil
+				}
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(strings.NewReader(`{"id":"evt_1"}`)),
+					Header:     make(http.Header),
+				}, nil
+			},
+		},
+	}
+}
+
+func TestTimer(t *testing.T) {
+	var bodies []map[string]interface{}
+	httpClient := createRecordingClient(&bodies, `{"id":"evt_1"}`)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(httpClient),
+		billing.WithoutBatching(),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	compute := sdk.Meter("meter_compute")
+	timer := sdk.StartTimer(compute, "user_1", billing.WithTimerUnit(time.Millisecond))
+
+	time.Sleep(30 * time.Millisecond)
+	timer.Pause()
+	time.Sleep(50 * time.Millisecond)
+	timer.Resume()
+	time.Sleep(30 * time.Millisecond)
+
+	if err := timer.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop error: %v", err)
+	}
+	if err := timer.Stop(context.Background()); err != nil {
+		t.Errorf("Expected second Stop to be a no-op, got %v", err)
+	}
+
+	if len(bodies) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(bodies))
+	}
+	// The pause is not billed
+	quantity, _ := bodies[0]["quantity"].(float64)
+	if quantity < 60 || quantity > 100 {
+		t.Errorf("Expected about 60ms of usage, got %v", quantity)
+	}
+	if bodies[0]["idempotency_key"] != timer.ID()+"-1" {
+		t.Errorf("Expected idempotency key %s-1, got %v", timer.ID(), bodies[0]["idempotency_key"])
+	}
+}
+
+func TestTimerHeartbeat(t *testing.T) {
+	var bodies []map[string]interface{}
+	httpClient := createRecordingClient(&bodies, `{"id":"evt_1"}`)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(httpClient),
+		billing.WithoutBatching(),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	timer := sdk.StartTimer(sdk.Meter("meter_compute"), "user_1",
+		billing.WithTimerUnit(time.Millisecond),
+		billing.WithHeartbeat(20*time.Millisecond),
+	)
+	time.Sleep(70 * time.Millisecond)
+	timer.Stop(context.Background())
+
+	if len(bodies) < 3 {
+		t.Fatalf("Expected heartbeat events, got %d events", len(bodies))
+	}
+
+	var total float64
+	keys := make(map[interface{}]bool)
+	for _, body := range bodies {
+		quantity, _ := body["quantity"].(float64)
+		total += quantity
+		keys[body["idempotency_key"]] = true
+	}
+	if len(keys) != len(bodies) {
+		t.Errorf("Expected unique idempotency keys, got %v", keys)
+	}
+	if elapsed := float64(timer.Elapsed()) / float64(time.Millisecond); total < elapsed-1 || total > elapsed+1 {
+		t.Errorf("Expected events to add up to %vms, got %vms", elapsed, total)
+	}
+}
+
+func TestTimerResendsFailedReport(t *testing.T) {
+	var mu sync.Mutex
+	var reports []report
+	var down atomic.Bool
+	down.Store(true)
+	httpClient := createFlakyClient(&mu, &reports, &down)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(httpClient),
+		billing.WithoutBatching(),
+		billing.WithRetry(billing.NoRetry),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	timer := sdk.StartTimer(sdk.Meter("meter_compute"), "user_1",
+		billing.WithTimerUnit(time.Millisecond),
+		billing.WithHeartbeat(20*time.Millisecond),
+	)
+	time.Sleep(50 * time.Millisecond)
+	down.Store(false)
+	time.Sleep(50 * time.Millisecond)
+	if err := timer.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// Failed heartbeats are resent unchanged until one succeeds
+	first := reports[0]
+	i := 0
+	for ; i < len(reports) && !reports[i].ok; i++ {
+		if reports[i] != (report{key: first.key, quantity: first.quantity}) {
+			t.Errorf("Expected failed report to be resent unchanged, got %+v after %+v", reports[i], first)
+		}
+	}
+	if i == 0 || i == len(reports) {
+		t.Fatalf("Expected failed and then successful reports, got %+v", reports)
+	}
+	if reports[i].key != first.key || reports[i].quantity != first.quantity {
+		t.Errorf("Expected the failed report to be resent first, got %+v after %+v", reports[i], first)
+	}
+
+	keys := make(map[string]bool)
+	for _, r := range reports[i:] {
+		if keys[r.key] {
+			t.Errorf("Expected each key to succeed once, got %s twice", r.key)
+		}
+		keys[r.key] = true
+	}
+	if len(keys) < 2 {
+		t.Errorf("Expected later time under new keys, got %+v", reports)
+	}
+}
+
+func TestTimerStopRetriesFailedReport(t *testing.T) {
+	var mu sync.Mutex
+	var reports []report
+	var down atomic.Bool
+	down.Store(true)
+	httpClient := createFlakyClient(&mu, &reports, &down)
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(httpClient),
+		billing.WithoutBatching(),
+		billing.WithRetry(billing.NoRetry),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	timer := sdk.StartTimer(sdk.Meter("meter_compute"), "user_1", billing.WithTimerUnit(time.Millisecond))
+	time.Sleep(20 * time.Millisecond)
+	if err := timer.Stop(context.Background()); err == nil {
+		t.Fatal("Expected Stop to fail while the API is down")
+	}
+
+	down.Store(false)
+	if err := timer.Stop(context.Background()); err != nil {
+		t.Fatalf("Expected second Stop to resend the report, got %v", err)
+	}
+	if err := timer.Stop(context.Background()); err != nil {
+		t.Errorf("Expected third Stop to be a no-op, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reports) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(reports))
+	}
+	if reports[1].key != reports[0].key || reports[1].quantity != reports[0].quantity || !reports[1].ok {
+		t.Errorf("Expected the same report to be resent, got %+v", reports)
+	}
+}
+
+func TestTimerAfterShutdown(t *testing.T) {
+	var bodies []map[string]interface{}
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(createRecordingClient(&bodies, `{"id":"evt_1"}`)),
+		billing.WithoutBatching(),
+	)
+	sdk.Shutdown(context.Background())
+
+	timer := sdk.StartTimer(sdk.Meter("meter_compute"), "user_1", billing.WithHeartbeat(time.Millisecond))
+	time.Sleep(10 * time.Millisecond)
+	if err := timer.Stop(context.Background()); !errors.Is(err, billing.ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+	if len(bodies) != 0 {
+		t.Errorf("Expected no events, got %d", len(bodies))
+	}
+}
@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Fluxratehq/fluxrate-golang-sdk/billing"
+)
+
+// Helper function to create a mock HTTP client that records the request bodies
+func createRecordingClient(bodies *[]map[string]interface{}, response string) *http.Client {
+	return &http.Client{
+		Transport: &MockRoundTripper{
+			RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+				var body map[string]interface{}
+				json.NewDecoder(req.Body).Decode(&body)
+				*bodies = append(*bodies, body)
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(strings.NewReader(response)),
+					Header:     make(http.Header),
+				}, nil
+			},
+		},
+	}
+}
+
+func TestTimestamps(t *testing.T) {
+	var bodies []map[string]interface{}
+	client := createRecordingClient(&bodies, `{
+		"id": "evt_1",
+		"timestamp": "2024-12-30T10:00:00.123456",
+		"created_at": "2024-12-30T11:00:00.5+01:00"
+	}`)
+
+	sdk, _ := billing.NewSDK(billing.Config{
+		APIKey:     "sk_test_123",
+		HTTPClient: client,
+	})
+	defer sdk.Shutdown(context.Background())
+
+	t.Run("Sub-Second Precision In UTC", func(t *testing.T) {
+		bodies = nil
+		loc := time.FixedZone("UTC+2", 2*60*60)
+		ts := time.Date(2024, 12, 30, 12, 0, 0, 123456789, loc)
+
+		_, err := sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: "user_1",
+			Quantity:           1,
+			Timestamp:          &ts,
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := bodies[0]["timestamp"]; got != "2024-12-30T10:00:00.123456789Z" {
+			t.Errorf("Expected UTC nanosecond timestamp, got %v", got)
+		}
+	})
+
+	t.Run("Timestamp Defaults To Track Time", func(t *testing.T) {
+		bodies = nil
+		before := time.Now()
+		sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: "user_1",
+			Quantity:           1,
+		})
+
+		sent, err := time.Parse(time.RFC3339Nano, bodies[0]["timestamp"].(string))
+		if err != nil {
+			t.Fatalf("Failed to parse sent timestamp: %v", err)
+		}
+		if sent.Before(before.Add(-time.Millisecond)) || sent.After(time.Now()) {
+			t.Errorf("Expected timestamp at Track time, got %v", sent)
+		}
+	})
+
+	t.Run("Parsed Response Times", func(t *testing.T) {
+		resp, err := sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: "user_1",
+			Quantity:           1,
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		wantEvent := time.Date(2024, 12, 30, 10, 0, 0, 123456000, time.UTC)
+		wantCreated := time.Date(2024, 12, 30, 10, 0, 0, 500000000, time.UTC)
+		if !resp.EventTime.Equal(wantEvent) || resp.EventTime.Location() != time.UTC {
+			t.Errorf("Expected EventTime %v, got %v", wantEvent, resp.EventTime)
+		}
+		if !resp.CreatedTime.Equal(wantCreated) {
+			t.Errorf("Expected CreatedTime %v, got %v", wantCreated, resp.CreatedTime)
+		}
+	})
+}
+
+func TestLateEvents(t *testing.T) {
+	var bodies []map[string]interface{}
+	client := createRecordingClient(&bodies, `{"id": "evt_1"}`)
+
+	old := time.Now().AddDate(0, -2, 0)
+
+	t.Run("Reject", func(t *testing.T) {
+		bodies = nil
+		sdk, _ := billing.New("sk_test_123",
+			billing.WithHTTPClient(client),
+			billing.WithoutBatching(),
+			billing.WithLateEvents(billing.LateEventPolicy{Mode: billing.LateEventsReject}),
+		)
+		defer sdk.Shutdown(context.Background())
+
+		_, err := sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: "user_1",
+			Quantity:           1,
+			Timestamp:          &old,
+		})
+		var verr *billing.ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("Expected *ValidationError, got %v", err)
+		}
+		if len(bodies) != 0 {
+			t.Errorf("Expected no request for rejected event, got %d", len(bodies))
+		}
+	})
+
+	t.Run("Clamp", func(t *testing.T) {
+		bodies = nil
+		sdk, _ := billing.New("sk_test_123",
+			billing.WithHTTPClient(client),
+			billing.WithoutBatching(),
+			billing.WithLateEvents(billing.LateEventPolicy{
+				Mode:   billing.LateEventsClamp,
+				Period: billing.DailyPeriod,
+			}),
+		)
+		defer sdk.Shutdown(context.Background())
+
+		_, err := sdk.Track(context.Background(), billing.TrackEventParams{
+			MeterToken:         "meter_123",
+			CustomerExternalID: "user_1",
+			Quantity:           1,
+			Timestamp:          &old,
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		start, _ := billing.DailyPeriod.Bounds(time.Now())
+		if got := bodies[0]["timestamp"]; got != start.Format(time.RFC3339Nano) {
+			t.Errorf("Expected timestamp clamped to %v, got %v", start, got)
+		}
+	})
+}
+
+func TestPeriods(t *testing.T) {
+	ts := time.Date(2024, 2, 29, 23, 30, 0, 0, time.UTC)
+
+	start, end := billing.MonthlyPeriod.Bounds(ts)
+	if !start.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected monthly bounds: %v - %v", start, end)
+	}
+
+	start, end = billing.DailyPeriod.Bounds(ts)
+	if !start.Equal(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected daily bounds: %v - %v", start, end)
+	}
+
+	start, end = billing.HourlyPeriod.Bounds(ts)
+	if !start.Equal(time.Date(2024, 2, 29, 23, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected hourly bounds: %v - %v", start, end)
+	}
+}
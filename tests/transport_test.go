@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Fluxratehq/fluxrate-golang-sdk/billing"
+)
+
+func TestMeteringTransport(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write(make([]byte, 1500))
+	}))
+	defer upstream.Close()
+
+	var bodies []map[string]interface{}
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(createRecordingClient(&bodies, `{"id":"evt_1"}`)),
+		billing.WithBatching(100, time.Hour),
+		billing.WithMaxConcurrentRequests(1),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	client := &http.Client{Transport: &billing.MeteringTransport{
+		Calls: sdk.Meter("meter_calls"),
+		StatusMeters: map[string]*billing.Meter{
+			"4xx": sdk.Meter("meter_client_errors"),
+		},
+		ResponseBytes:    sdk.Meter("meter_bytes"),
+		ResponseByteUnit: billing.KB,
+	}}
+
+	get := func(ctx context.Context, path string) {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, upstream.URL+path, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Request error: %v", err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	// Requests without a customer are not metered
+	get(context.Background(), "/ok")
+	sdk.Flush(context.Background())
+	if len(bodies) != 0 {
+		t.Fatalf("Expected no events without a customer, got %d", len(bodies))
+	}
+
+	ctx := billing.WithCustomer(context.Background(), "user_1")
+	get(ctx, "/ok")
+	get(ctx, "/missing")
+	sdk.Flush(context.Background())
+
+	counts := make(map[string]float64)
+	for _, body := range bodies {
+		if body["customer_external_id"] != "user_1" {
+			t.Errorf("Expected customer user_1, got %v", body["customer_external_id"])
+		}
+		counts[body["meter_token"].(string)] += body["quantity"].(float64)
+	}
+	if counts["meter_calls"] != 2 || counts["meter_client_errors"] != 1 || counts["meter_bytes"] != 3 {
+		t.Errorf("Unexpected metered usage: %v", counts)
+	}
+}
+
+func TestMeteringTransportDoesNotWaitForTracking(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer upstream.Close()
+
+	// The Fluxrate API takes 200ms per event
+	var requestCount int64
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(createSlowClient(&requestCount, 200*time.Millisecond)),
+		billing.WithoutBatching(),
+	)
+	client := &http.Client{Transport: &billing.MeteringTransport{
+		Calls:         sdk.Meter("meter_calls"),
+		ResponseBytes: sdk.Meter("meter_bytes"),
+	}}
+
+	ctx, cancel := context.WithCancel(billing.WithCustomer(context.Background(), "user_1"))
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, upstream.URL, nil)
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Request error: %v", err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Expected the response without waiting for metering, took %v", elapsed)
+	}
+
+	// Cancelling the request context does not cancel the metering event
+	cancel()
+	if err := sdk.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown error: %v", err)
+	}
+	if stats := sdk.Stats(); stats.Sent != 2 {
+		t.Errorf("Expected the call and its bytes to be metered, got %d sent, last error %v", stats.Sent, stats.LastError)
+	}
+}
+
+func TestMeteringTransportSkipsSDKRequests(t *testing.T) {
+	var bodies []map[string]interface{}
+	transport := &billing.MeteringTransport{
+		Base: createRecordingClient(&bodies, `{"id":"evt_1"}`).Transport,
+	}
+
+	sdk, _ := billing.New("sk_test_123",
+		billing.WithHTTPClient(&http.Client{Transport: transport}),
+		billing.WithoutBatching(),
+	)
+	defer sdk.Shutdown(context.Background())
+	transport.Calls = sdk.Meter("meter_calls")
+
+	ctx := billing.WithCustomer(context.Background(), "user_1")
+	if _, err := sdk.Track(ctx, billing.TrackEventParams{
+		MeterToken:         "meter_123",
+		CustomerExternalID: "user_1",
+		Quantity:           1,
+	}); err != nil {
+		t.Fatalf("Track error: %v", err)
+	}
+
+	// Only the tracked event itself reaches the API
+	if len(bodies) != 1 {
+		t.Errorf("Expected 1 request, got %d", len(bodies))
+	}
+}